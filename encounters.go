@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+	pokeapi "github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+// maxWalkSteps stops `walk` from running forever in areas with a tiny encounter rate
+const maxWalkSteps = 50
+
+// wildEncounter is the wild pokemon the player ran into and can catch or battle
+type wildEncounter struct {
+	Base    pokeapi.Pokemon
+	Pokemon *game.BattlePokemon
+}
+
+// encounterTable loads the slots that can appear right now in the area the player is exploring
+func encounterTable(cfg *Config) (game.EncounterTable, error) {
+	if cfg.CurrentArea == "" {
+		return game.EncounterTable{}, fmt.Errorf("you're not anywhere yet. Use 'travel' to pick somewhere to go")
+	}
+	area, err := cfg.Pokeapi.GetLocationArea(cfg.CurrentArea)
+	if err != nil {
+		return game.EncounterTable{}, err
+	}
+	return game.NewEncounterTable(area, game.DefaultVersion).At(timeOfDay(cfg)), nil
+}
+
+func printEncounterTable(table game.EncounterTable) {
+	if len(table.Encounters) == 0 {
+		fmt.Println("No wild Pokemon live here.")
+		return
+	}
+	for _, method := range table.Methods() {
+		fmt.Printf("%s:\n", methodLabel(method))
+		for _, e := range table.Slots(method) {
+			if times := e.Times(); len(times) > 0 {
+				fmt.Printf(" - %s (Lvl %s, %d%%, %s only)\n", e.Species, levelRange(e), e.Chance, strings.Join(times, "/"))
+				continue
+			}
+			fmt.Printf(" - %s (Lvl %s, %d%%)\n", e.Species, levelRange(e), e.Chance)
+		}
+	}
+}
+
+func methodLabel(method string) string {
+	switch method {
+	case game.MethodWalk:
+		return "Walking in tall grass"
+	case "surf":
+		return "Surfing"
+	case "old-rod":
+		return "Fishing with an Old Rod"
+	case "good-rod":
+		return "Fishing with a Good Rod"
+	case "super-rod":
+		return "Fishing with a Super Rod"
+	}
+	return strings.ReplaceAll(method, "-", " ")
+}
+
+func levelRange(e game.Encounter) string {
+	if e.MinLevel == e.MaxLevel {
+		return strconv.Itoa(e.MinLevel)
+	}
+	return fmt.Sprintf("%d-%d", e.MinLevel, e.MaxLevel)
+}
+
+// commandWalk walks through the area's tall grass until something jumps out
+func commandWalk(cfg *Config, args []string) error {
+	return wander(cfg, game.MethodWalk, args)
+}
+
+// commandSurf swims around the area's water until something jumps out
+func commandSurf(cfg *Config, args []string) error {
+	return wander(cfg, game.MethodSurf, args)
+}
+
+// wander takes steps with an encounter method until a wild pokemon appears.
+// Each step wears down the player's repel.
+func wander(cfg *Config, method string, args []string) error {
+	steps := maxWalkSteps
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > maxWalkSteps {
+			return fmt.Errorf("usage: %s [steps, 1-%d]", method, maxWalkSteps)
+		}
+		steps = n
+	} else if len(args) > 1 {
+		return fmt.Errorf("usage: %s [steps, 1-%d]", method, maxWalkSteps)
+	}
+
+	table, err := encounterTable(cfg)
+	if err != nil {
+		return err
+	}
+	if len(table.Slots(method)) == 0 {
+		return fmt.Errorf("you can't %s in %s. Use 'explore' to see what's here", method, cfg.CurrentArea)
+	}
+	if err := game.CanUseMethod(method, &cfg.Inventory, cfg.Party); err != nil {
+		return err
+	}
+
+	cfg.Wild = nil
+	for step := 1; step <= steps; step++ {
+		// Walking around with your team brings you closer
+		cfg.Steps = game.WalkTogether(cfg.Party, cfg.Steps)
+		found := table.Step(method, cfg.RNG)
+		repelled := cfg.Inventory.RepelSteps > 0
+		if cfg.Inventory.RepelStep() {
+			fmt.Println("The repel's effect wore off...")
+		}
+		if !found {
+			continue
+		}
+
+		e, level, ok := table.Roll(method, cfg.RNG)
+		if !ok || repelled && game.RepelBlocks(level, cfg.Party) {
+			continue
+		}
+		fmt.Printf("You went %d steps...\n", step)
+		return spawnWild(cfg, e, level)
+	}
+	fmt.Printf("You went %d steps, but nothing appeared.\n", steps)
+	return saveGame(cfg)
+}
+
+// commandFish casts a rod into the area's water
+func commandFish(cfg *Config, args []string) error {
+	rod := cfg.Inventory.BestRod()
+	if len(args) == 1 {
+		rod = args[0]
+	} else if len(args) > 1 {
+		return fmt.Errorf("usage: fish [old-rod|good-rod|super-rod]")
+	}
+	if rod == "" {
+		return fmt.Errorf("you don't have a fishing rod. The shop sells them")
+	}
+	if !game.IsRod(rod) {
+		return fmt.Errorf("%s isn't a fishing rod", rod)
+	}
+	if err := game.CanUseMethod(rod, &cfg.Inventory, cfg.Party); err != nil {
+		return err
+	}
+
+	table, err := encounterTable(cfg)
+	if err != nil {
+		return err
+	}
+	if len(table.Slots(rod)) == 0 {
+		return fmt.Errorf("nothing bites on the %s in %s", rod, cfg.CurrentArea)
+	}
+
+	cfg.Wild = nil
+	if !table.Step(rod, cfg.RNG) {
+		fmt.Println("Not even a nibble...")
+		return nil
+	}
+	e, level, ok := table.Roll(rod, cfg.RNG)
+	if !ok {
+		fmt.Println("Not even a nibble...")
+		return nil
+	}
+	fmt.Println("Oh! A bite!")
+	return spawnWild(cfg, e, level)
+}
+
+// commandRepel uses a repel from the bag, the weakest one owned unless told otherwise
+func commandRepel(cfg *Config, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: repel [repel|super-repel|max-repel]")
+	}
+	if cfg.Inventory.Repels == nil {
+		cfg.Inventory.Repels = make(map[string]int)
+	}
+	kind := ""
+	if len(args) == 1 {
+		kind = args[0]
+	} else {
+		for _, r := range []string{"repel", "super-repel", "max-repel"} {
+			if cfg.Inventory.Repels[r] > 0 {
+				kind = r
+				break
+			}
+		}
+		if kind == "" {
+			return fmt.Errorf("you don't have any repels")
+		}
+	}
+
+	if err := cfg.Inventory.UseRepel(kind); err != nil {
+		return err
+	}
+	fmt.Printf("Used a %s! Weak wild Pokemon will stay away for %d steps.\n", kind, cfg.Inventory.RepelSteps)
+	return saveGame(cfg)
+}
+
+// commandEncounter looks for a wild pokemon with a method, finding one straight away
+func commandEncounter(cfg *Config, args []string) error {
+	method := game.MethodWalk
+	if len(args) == 1 {
+		method = args[0]
+	} else if len(args) > 1 {
+		return fmt.Errorf("usage: encounter [method]")
+	}
+
+	table, err := encounterTable(cfg)
+	if err != nil {
+		return err
+	}
+	if len(table.Slots(method)) == 0 {
+		methods := table.Methods()
+		if len(methods) == 0 {
+			return fmt.Errorf("no wild Pokemon live in %s", cfg.CurrentArea)
+		}
+		return fmt.Errorf("you can't find Pokemon by %s here. Try: %v", method, methods)
+	}
+	if err := game.CanUseMethod(method, &cfg.Inventory, cfg.Party); err != nil {
+		return err
+	}
+
+	e, level, ok := table.Roll(method, cfg.RNG)
+	if !ok {
+		return fmt.Errorf("nothing appeared")
+	}
+	return spawnWild(cfg, e, level)
+}
+
+// spawnWild makes a rolled encounter the current wild pokemon
+func spawnWild(cfg *Config, e game.Encounter, level int) error {
+	base, err := cfg.Pokeapi.GetPokemon(e.Species)
+	if err != nil {
+		return err
+	}
+	wild, err := game.NewBattlePokemon(base, level, cfg.Pokeapi, cfg.RNG)
+	if err != nil {
+		return fmt.Errorf("failed to create pokemon: %w", err)
+	}
+
+	cfg.Wild = &wildEncounter{Base: base, Pokemon: wild}
+	fmt.Printf("A wild %s (Lvl %d) appeared!\n", base.Name, level)
+	fmt.Println("Use 'catch' to try to catch it or 'battle' to fight it.")
+	return nil
+}
+
+// currentWild is the wild pokemon the player ran into, checking it's the one they asked for
+func currentWild(cfg *Config, name string) (*wildEncounter, error) {
+	if cfg.Wild == nil {
+		return nil, fmt.Errorf("there's no wild Pokemon around. Use 'walk' to look for one")
+	}
+	if name != "" && name != cfg.Wild.Base.Name {
+		return nil, fmt.Errorf("there's no %s here, only a wild %s", name, cfg.Wild.Base.Name)
+	}
+	return cfg.Wild, nil
+}
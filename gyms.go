@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+)
+
+func commandGym(cfg *Config, args []string) error {
+	next, hasNext := game.NextGym(cfg.Gyms, cfg.Badges)
+
+	if len(args) == 0 {
+		fmt.Println("--- Gym Challenge ---")
+		for _, g := range cfg.Gyms {
+			status := "locked"
+			switch {
+			case cfg.Badges.Has(g):
+				status = "badge earned"
+			case hasNext && g.Number == next.Number:
+				status = "next challenge"
+			}
+			fmt.Printf("%d. %s Gym (%s) - Leader %s, %s (%s)\n", g.Number, g.City, g.Type, g.Leader.Name, g.BadgeLabel(), status)
+		}
+		fmt.Println("\nUsage: gym <number>")
+		return nil
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: gym [number]")
+	}
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 || number > len(cfg.Gyms) {
+		return fmt.Errorf("there are gyms numbered 1 to %d", len(cfg.Gyms))
+	}
+	gym := cfg.Gyms[number-1]
+	if cfg.Badges.Has(gym) {
+		return fmt.Errorf("you already have the %s", gym.BadgeLabel())
+	}
+	if !hasNext || gym.Number != next.Number {
+		return fmt.Errorf("the %s Gym only accepts challengers with the %s", gym.City, next.BadgeLabel())
+	}
+
+	fmt.Printf("Welcome to the %s Gym! Leader %s accepts your challenge!\n", gym.City, gym.Leader.Name)
+	team, err := gym.Leader.BuildTeam(cfg.Pokeapi, cfg.RNG)
+	if err != nil {
+		return err
+	}
+
+	leader := gym.Leader
+	outcome, err := runBattle(cfg, game.BattleConfig{
+		Trainer:     &leader,
+		TrainerTeam: team,
+		Double:      leader.Double,
+	})
+	if err != nil {
+		return err
+	}
+
+	if outcome == game.OutcomeWon {
+		cfg.Badges[gym.Badge] = true
+		fmt.Printf("\nYou received the %s!\n", gym.BadgeLabel())
+		fmt.Printf("Pokemon up to level %d will now obey you.\n", game.ObedienceLevel(cfg.Gyms, cfg.Badges))
+		for _, item := range gym.Unlocks {
+			fmt.Printf("The PokeMart now sells %s.\n", item)
+		}
+		if _, more := game.NextGym(cfg.Gyms, cfg.Badges); !more {
+			fmt.Println("You've collected every badge! You're ready for the Pokemon League!")
+		}
+	}
+	return saveGame(cfg)
+}
+
+func commandBadges(cfg *Config, args []string) error {
+	fmt.Printf("--- Badge Case (%d/%d) ---\n", len(cfg.Badges), len(cfg.Gyms))
+	for _, g := range cfg.Gyms {
+		if cfg.Badges.Has(g) {
+			fmt.Printf("[*] %s (%s, %s-type)\n", g.BadgeLabel(), g.City, g.Type)
+		} else {
+			fmt.Printf("[ ] ???\n")
+		}
+	}
+	fmt.Printf("Pokemon up to level %d obey you.\n", game.ObedienceLevel(cfg.Gyms, cfg.Badges))
+	return nil
+}
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AIActionKind is what an AI-controlled opponent decided to do
+type AIActionKind int
+
+const (
+	AIUseMove AIActionKind = iota
+	AISwitch
+	AIUsePotion
+)
+
+// AIAction is an opponent's choice for the turn
+type AIAction struct {
+	Kind   AIActionKind
+	Move   int // Index into Self.Moves for AIUseMove
+	Target int // Index into the view's foes for single-target moves
+	Switch int // Index into Team for AISwitch
+}
+
+// AIView is everything an AI is allowed to look at when choosing
+type AIView struct {
+	Self    *BattlePokemon
+	Foe     *BattlePokemon
+	Foes    []*BattlePokemon // Every foe on the field in double battles, starting with Foe
+	Team    []*BattlePokemon // Self's side, including Self. Empty for wild pokemon.
+	Allies  []*BattlePokemon // Self's partners already on the field
+	Potions int
+}
+
+// foes is every pokemon the AI can aim at
+func (v AIView) foes() []*BattlePokemon {
+	if len(v.Foes) == 0 {
+		return []*BattlePokemon{v.Foe}
+	}
+	return v.Foes
+}
+
+// AI picks an opponent's action. Implementations must only draw
+// randomness from rng so battles are reproducible with a seed.
+type AI interface {
+	Choose(view AIView, rng *RNG) AIAction
+}
+
+// RandomAI picks any move with PP left
+type RandomAI struct{}
+
+// GreedyAI picks the move with the highest raw damage, ignoring types
+type GreedyAI struct{}
+
+// TypeAwareAI picks the move with the highest expected damage after type matchups, STAB and accuracy
+type TypeAwareAI struct{}
+
+// TrainerAI plays like a trainer: heals when low, switches out of bad matchups, otherwise attacks smartly
+type TrainerAI struct{}
+
+var aiStrategies = map[string]AI{
+	"random":  RandomAI{},
+	"greedy":  GreedyAI{},
+	"smart":   TypeAwareAI{},
+	"trainer": TrainerAI{},
+}
+
+// AINames lists the strategies NewAI accepts
+func AINames() []string {
+	names := make([]string, 0, len(aiStrategies))
+	for name := range aiStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewAI looks a strategy up by name
+func NewAI(name string) (AI, error) {
+	ai, ok := aiStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown AI '%s' (choose from %v)", name, AINames())
+	}
+	return ai, nil
+}
+
+// usableMoves returns the indexes of moves with PP left, or every move if all are empty
+func usableMoves(p *BattlePokemon) []int {
+	var usable []int
+	for i, m := range p.Moves {
+		if m.CurrentPP > 0 {
+			usable = append(usable, i)
+		}
+	}
+	if len(usable) == 0 {
+		for i := range p.Moves {
+			usable = append(usable, i)
+		}
+	}
+	return usable
+}
+
+func (RandomAI) Choose(view AIView, rng *RNG) AIAction {
+	usable := usableMoves(view.Self)
+	return AIAction{Kind: AIUseMove, Move: usable[rng.Intn(len(usable))], Target: rng.Intn(len(view.foes()))}
+}
+
+func (GreedyAI) Choose(view AIView, rng *RNG) AIAction {
+	move, target := bestMove(view.Self, view.foes(), false)
+	return AIAction{Kind: AIUseMove, Move: move, Target: target}
+}
+
+func (TypeAwareAI) Choose(view AIView, rng *RNG) AIAction {
+	move, target := bestMove(view.Self, view.foes(), true)
+	return AIAction{Kind: AIUseMove, Move: move, Target: target}
+}
+
+func (TrainerAI) Choose(view AIView, rng *RNG) AIAction {
+	self := view.Self
+
+	// 1. Heal when in the red
+	if view.Potions > 0 && self.Stats.HP*4 <= self.Stats.MaxHP {
+		return AIAction{Kind: AIUsePotion}
+	}
+
+	// 2. Switch if a foe threatens a KO or hits super effectively,
+	// and a teammate takes that hit better
+	threatener := view.Foe
+	threat := bestDamage(threatener, self)
+	for _, foe := range view.foes() {
+		if d := bestDamage(foe, self); d.damage > threat.damage {
+			threatener, threat = foe, d
+		}
+	}
+	if threat.damage >= float64(self.Stats.HP) || threat.effectiveness >= 2 {
+		bestIdx := -1
+		bestRatio := threat.damage / float64(max(self.Stats.HP, 1))
+		for i, mate := range view.Team {
+			if mate == self || mate.Status == StatusFainted || mate.Stats.HP <= 0 || view.onField(mate) {
+				continue
+			}
+			incoming := bestDamage(threatener, mate)
+			ratio := incoming.damage / float64(mate.Stats.HP)
+			if incoming.effectiveness < threat.effectiveness && ratio < bestRatio {
+				bestIdx, bestRatio = i, ratio
+			}
+		}
+		if bestIdx >= 0 {
+			return AIAction{Kind: AISwitch, Switch: bestIdx}
+		}
+	}
+
+	// 3. Otherwise hit as hard as possible
+	return TypeAwareAI{}.Choose(view, rng)
+}
+
+// bestMove picks the move and foe that deal the most damage. Spread moves
+// are scored by their total damage across every foe.
+func bestMove(attacker *BattlePokemon, foes []*BattlePokemon, typeAware bool) (move, target int) {
+	best, bestTarget, bestDamage := -1, 0, -1.0
+	for _, i := range usableMoves(attacker) {
+		m := &attacker.Moves[i]
+		if m.IsSpread() && len(foes) > 1 {
+			total := 0.0
+			for _, foe := range foes {
+				total += EstimateDamage(attacker, foe, m, typeAware) * SpreadMultiplier
+			}
+			if total > bestDamage {
+				best, bestTarget, bestDamage = i, 0, total
+			}
+			continue
+		}
+		for t, foe := range foes {
+			damage := EstimateDamage(attacker, foe, m, typeAware)
+			if damage > bestDamage {
+				best, bestTarget, bestDamage = i, t, damage
+			}
+		}
+	}
+	return best, bestTarget
+}
+
+type damageEstimate struct {
+	damage        float64
+	effectiveness float64
+}
+
+// onField reports whether a teammate is already battling next to Self
+func (v AIView) onField(p *BattlePokemon) bool {
+	for _, ally := range v.Allies {
+		if ally == p {
+			return true
+		}
+	}
+	return false
+}
+
+// bestDamage is the most damage attacker could expect to deal this turn
+func bestDamage(attacker, defender *BattlePokemon) damageEstimate {
+	var best damageEstimate
+	for _, i := range usableMoves(attacker) {
+		move := &attacker.Moves[i]
+		damage := EstimateDamage(attacker, defender, move, true)
+		if damage > best.damage {
+			best = damageEstimate{damage: damage, effectiveness: GetTypeEffectiveness(move.Type, defender.TypeNames())}
+		}
+	}
+	return best
+}
+
+// EstimateDamage is the average damage a move would do, without randomness.
+// With typeAware it also factors in type effectiveness, STAB and accuracy.
+func EstimateDamage(attacker, defender *BattlePokemon, move *Move, typeAware bool) float64 {
+	if !move.HitsOpponents() {
+		return 0
+	}
+	damage := baseDamage(attacker, defender, move)
+	if typeAware {
+		damage *= GetTypeEffectiveness(move.Type, defender.TypeNames()) * stab(attacker, move)
+		if move.Accuracy > 0 {
+			damage *= float64(move.Accuracy) / 100.0
+		}
+	}
+	return damage
+}
@@ -0,0 +1,110 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+// newTestMon builds a battle-ready pokemon without touching the API
+func newTestMon(t *testing.T, name string, level int, types ...string) *BattlePokemon {
+	t.Helper()
+	typeJSON := make([]string, len(types))
+	for i, typeName := range types {
+		typeJSON[i] = fmt.Sprintf(`{"type": {"name": %q}}`, typeName)
+	}
+	raw := fmt.Sprintf(`{
+		"name": %q,
+		"base_experience": 64,
+		"stats": [
+			{"base_stat": 50, "stat": {"name": "hp"}},
+			{"base_stat": 50, "stat": {"name": "attack"}},
+			{"base_stat": 50, "stat": {"name": "defense"}},
+			{"base_stat": 50, "stat": {"name": "speed"}}
+		],
+		"types": [%s]
+	}`, name, strings.Join(typeJSON, ","))
+
+	var base pokeapi.Pokemon
+	if err := json.Unmarshal([]byte(raw), &base); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	p := &BattlePokemon{Base: base, Nickname: name, Level: level}
+	p.RecalculateStats()
+	p.Stats.HP = p.Stats.MaxHP
+	return p
+}
+
+func TestGreedyAndTypeAwareAI(t *testing.T) {
+	attacker := newTestMon(t, "pikachu", 20, "electric")
+	attacker.Moves = []Move{
+		{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35},
+		{Name: "thunder-shock", Type: "electric", Power: 40, Accuracy: 100, CurrentPP: 30},
+		{Name: "slam", Type: "normal", Power: 80, Accuracy: 75, CurrentPP: 20},
+	}
+	defender := newTestMon(t, "squirtle", 20, "water")
+	view := AIView{Self: attacker, Foe: defender}
+	rng := NewRNG(1)
+
+	if action := (GreedyAI{}).Choose(view, rng); action.Move != 2 {
+		t.Errorf("greedy: expected the 80 power move, got %s", attacker.Moves[action.Move].Name)
+	}
+	if action := (TypeAwareAI{}).Choose(view, rng); action.Move != 1 {
+		t.Errorf("type-aware: expected the super effective STAB move, got %s", attacker.Moves[action.Move].Name)
+	}
+
+	attacker.Moves[1].CurrentPP = 0
+	if action := (TypeAwareAI{}).Choose(view, rng); action.Move == 1 {
+		t.Errorf("type-aware: picked a move with no PP")
+	}
+}
+
+func TestTrainerAI(t *testing.T) {
+	foe := newTestMon(t, "squirtle", 30, "water")
+	foe.Moves = []Move{{Name: "water-gun", Type: "water", Power: 40, Accuracy: 100, CurrentPP: 25}}
+
+	charmander := newTestMon(t, "charmander", 10, "fire")
+	charmander.Moves = []Move{{Name: "ember", Type: "fire", Power: 40, Accuracy: 100, CurrentPP: 25}}
+	bulbasaur := newTestMon(t, "bulbasaur", 10, "grass")
+	bulbasaur.Moves = []Move{{Name: "vine-whip", Type: "grass", Power: 45, Accuracy: 100, CurrentPP: 25}}
+	team := []*BattlePokemon{charmander, bulbasaur}
+	rng := NewRNG(1)
+
+	action := (TrainerAI{}).Choose(AIView{Self: charmander, Foe: foe, Team: team}, rng)
+	if action.Kind != AISwitch || action.Switch != 1 {
+		t.Errorf("expected a switch to bulbasaur, got %+v", action)
+	}
+
+	charmander.Stats.HP = 1
+	action = (TrainerAI{}).Choose(AIView{Self: charmander, Foe: foe, Team: team, Potions: 1}, rng)
+	if action.Kind != AIUsePotion {
+		t.Errorf("expected a potion at low HP, got %+v", action)
+	}
+
+	action = (TrainerAI{}).Choose(AIView{Self: bulbasaur, Foe: foe, Team: team}, rng)
+	if action.Kind != AIUseMove {
+		t.Errorf("expected bulbasaur to attack, got %+v", action)
+	}
+}
+
+func TestRandomAIIsDeterministic(t *testing.T) {
+	self := newTestMon(t, "rattata", 5, "normal")
+	self.Moves = []Move{{Name: "a", CurrentPP: 1}, {Name: "b", CurrentPP: 1}, {Name: "c", CurrentPP: 1}, {Name: "d", CurrentPP: 1}}
+	view := AIView{Self: self, Foe: self}
+
+	pick := func(seed int64) []int {
+		rng := NewRNG(seed)
+		var moves []int
+		for i := 0; i < 20; i++ {
+			moves = append(moves, RandomAI{}.Choose(view, rng).Move)
+		}
+		return moves
+	}
+
+	if fmt.Sprint(pick(42)) != fmt.Sprint(pick(42)) {
+		t.Errorf("expected the same seed to give the same choices")
+	}
+}
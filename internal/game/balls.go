@@ -0,0 +1,134 @@
+package game
+
+import "strings"
+
+// CatchContext is the battle state conditional balls look at
+type CatchContext struct {
+	Wild          *BattlePokemon
+	Active        *BattlePokemon
+	Turn          int // Starts at 1
+	TimeOfDay     string
+	AlreadyCaught bool // Species is already in the Pokedex
+}
+
+// Ball is a kind of Poke Ball and how much it boosts the catch rate
+type Ball struct {
+	Name  string // Item name used in the shop and bag
+	Label string
+	Bonus func(ctx CatchContext) float64
+}
+
+func flatBonus(bonus float64) func(CatchContext) float64 {
+	return func(CatchContext) float64 { return bonus }
+}
+
+// Balls lists every ball in the order the bag shows them
+var Balls = []Ball{
+	{Name: "pokeball", Label: "Poke Ball", Bonus: flatBonus(1.0)},
+	{Name: "greatball", Label: "Great Ball", Bonus: flatBonus(1.5)},
+	{Name: "ultraball", Label: "Ultra Ball", Bonus: flatBonus(2.0)},
+	{Name: "masterball", Label: "Master Ball", Bonus: flatBonus(255.0)},
+	{Name: "net-ball", Label: "Net Ball", Bonus: func(ctx CatchContext) float64 {
+		if ctx.Wild.HasType("water") || ctx.Wild.HasType("bug") {
+			return 3.5
+		}
+		return 1.0
+	}},
+	{Name: "quick-ball", Label: "Quick Ball", Bonus: func(ctx CatchContext) float64 {
+		if ctx.Turn <= 1 {
+			return 5.0
+		}
+		return 1.0
+	}},
+	{Name: "timer-ball", Label: "Timer Ball", Bonus: func(ctx CatchContext) float64 {
+		// Grows by ~0.3 each turn, up to 4x after ten turns
+		return min(1.0+float64(ctx.Turn-1)*1229.0/4096.0, 4.0)
+	}},
+	{Name: "dusk-ball", Label: "Dusk Ball", Bonus: func(ctx CatchContext) float64 {
+		if IsDark(ctx.TimeOfDay) {
+			return 3.0
+		}
+		return 1.0
+	}},
+	{Name: "repeat-ball", Label: "Repeat Ball", Bonus: func(ctx CatchContext) float64 {
+		if ctx.AlreadyCaught {
+			return 3.5
+		}
+		return 1.0
+	}},
+	{Name: "level-ball", Label: "Level Ball", Bonus: func(ctx CatchContext) float64 {
+		if ctx.Active == nil {
+			return 1.0
+		}
+		switch mine, theirs := ctx.Active.Level, ctx.Wild.Level; {
+		case mine/4 > theirs:
+			return 8.0
+		case mine/2 > theirs:
+			return 4.0
+		case mine > theirs:
+			return 2.0
+		default:
+			return 1.0
+		}
+	}},
+	{Name: "heal-ball", Label: "Heal Ball", Bonus: flatBonus(1.0)},
+}
+
+// FindBall looks a ball up by item name
+func FindBall(name string) (Ball, bool) {
+	for _, b := range Balls {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return Ball{}, false
+}
+
+// IsBall reports whether an item name is a kind of Poke Ball
+func IsBall(name string) bool {
+	_, ok := FindBall(name)
+	return ok
+}
+
+// BallLabel is the display name for the ball a pokemon was caught in
+func BallLabel(name string) string {
+	if b, ok := FindBall(name); ok {
+		return b.Label
+	}
+	return strings.ReplaceAll(name, "-", " ")
+}
+
+// ballCounter returns the field the classic balls are stored in
+func (inv *PlayerInventory) ballCounter(name string) *int {
+	switch name {
+	case "pokeball":
+		return &inv.Pokeballs
+	case "greatball":
+		return &inv.Greatballs
+	case "ultraball":
+		return &inv.Ultraballs
+	case "masterball":
+		return &inv.Masterballs
+	}
+	return nil
+}
+
+// BallCount returns how many of a ball are in the bag
+func (inv *PlayerInventory) BallCount(name string) int {
+	if counter := inv.ballCounter(name); counter != nil {
+		return *counter
+	}
+	return inv.Balls[name]
+}
+
+// AddBall puts balls in the bag
+func (inv *PlayerInventory) AddBall(name string, amount int) {
+	if counter := inv.ballCounter(name); counter != nil {
+		*counter += amount
+		return
+	}
+	if inv.Balls == nil {
+		inv.Balls = make(map[string]int)
+	}
+	inv.Balls[name] += amount
+}
@@ -0,0 +1,48 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+func TestBallBonus(t *testing.T) {
+	var magikarp pokeapi.Pokemon
+	if err := json.Unmarshal([]byte(`{"name": "magikarp", "types": [{"type": {"name": "water"}}]}`), &magikarp); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	wild := &BattlePokemon{Base: magikarp, Level: 5}
+
+	cases := []struct {
+		ball     string
+		ctx      CatchContext
+		expected float64
+	}{
+		{ball: "greatball", ctx: CatchContext{Turn: 3}, expected: 1.5},
+		{ball: "net-ball", ctx: CatchContext{Turn: 1}, expected: 3.5},
+		{ball: "quick-ball", ctx: CatchContext{Turn: 1}, expected: 5.0},
+		{ball: "quick-ball", ctx: CatchContext{Turn: 2}, expected: 1.0},
+		{ball: "timer-ball", ctx: CatchContext{Turn: 30}, expected: 4.0},
+		{ball: "dusk-ball", ctx: CatchContext{Turn: 1, TimeOfDay: "night"}, expected: 3.0},
+		{ball: "dusk-ball", ctx: CatchContext{Turn: 1, TimeOfDay: "day"}, expected: 1.0},
+		{ball: "repeat-ball", ctx: CatchContext{Turn: 1, AlreadyCaught: true}, expected: 3.5},
+		{ball: "level-ball", ctx: CatchContext{Turn: 1, Active: &BattlePokemon{Level: 24}}, expected: 8.0},
+		{ball: "level-ball", ctx: CatchContext{Turn: 1, Active: &BattlePokemon{Level: 20}}, expected: 4.0},
+		{ball: "level-ball", ctx: CatchContext{Turn: 1, Active: &BattlePokemon{Level: 10}}, expected: 2.0},
+		{ball: "level-ball", ctx: CatchContext{Turn: 1, Active: &BattlePokemon{Level: 6}}, expected: 2.0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.ball, func(t *testing.T) {
+			ball, ok := FindBall(c.ball)
+			if !ok {
+				t.Fatalf("unknown ball %s", c.ball)
+			}
+			c.ctx.Wild = wild
+			if actual := ball.Bonus(c.ctx); actual != c.expected {
+				t.Errorf("expected %.2fx, got %.2fx", c.expected, actual)
+			}
+		})
+	}
+}
@@ -1,300 +1,1268 @@
 package game
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"math/rand"
-	"os"
-	"strconv"
+	"sort"
 
 	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
 )
 
-// StartBattle is the main entry point
-func StartBattle(party []*BattlePokemon, wildPokemon *BattlePokemon, inventory *PlayerInventory, client pokeapi.Client) bool {
-	scanner := bufio.NewScanner(os.Stdin)
+// BattleOutcome is how a battle ended
+type BattleOutcome int
 
-	// Get first alive pokemon
-	var activeMon *BattlePokemon
-	for _, p := range party {
-		if p.Status != StatusFainted && p.Stats.HP > 0 {
-			activeMon = p
-			break
+const (
+	OutcomeOngoing BattleOutcome = iota
+	OutcomeWon
+	OutcomeLost
+	OutcomeFled
+	OutcomeCaught
+)
+
+func (o BattleOutcome) String() string {
+	switch o {
+	case OutcomeWon:
+		return "won"
+	case OutcomeLost:
+		return "lost"
+	case OutcomeFled:
+		return "fled"
+	case OutcomeCaught:
+		return "caught"
+	default:
+		return "ongoing"
+	}
+}
+
+// ActionKind is what the player chose to do this turn
+type ActionKind int
+
+const (
+	ActionMove ActionKind = iota
+	ActionItem
+	ActionSwitch
+	ActionRun
+)
+
+// Action is one player input to the battle engine
+type Action struct {
+	Kind      ActionKind
+	Move      int    // Index into the active pokemon's moves
+	FoeTarget int    // Field position on the other side for single-target moves in double battles
+	Item      string // Ball or healing item name, as sold in the shop
+	Target    int    // Party index for switches and healing items
+}
+
+// BattleConfig sets up a battle against either a wild pokemon or a trainer
+type BattleConfig struct {
+	Party         []*BattlePokemon
+	Wild          *BattlePokemon   // For wild battles
+	Trainer       *Trainer         // For trainer battles, who fight with TrainerTeam
+	TrainerTeam   []*BattlePokemon // Built with Trainer.BuildTeam
+	Double        bool             // Two pokemon a side. Only trainers battle like this.
+	OpponentAI    AI               // nil for RandomAI in the wild, the trainer's own AI otherwise
+	Inventory     *PlayerInventory
+	AlreadyCaught bool            // Wild species is in the Pokedex (for the Repeat Ball)
+	TimeOfDay     string          // For the Dusk Ball and evolutions
+	Location      string          // Where the battle happens, for location-based evolutions
+	Weather       Weather         // Weather at the start, lasting until something replaces it
+	Client        *pokeapi.Client // nil skips XP and evolution, e.g. in tests
+	RNG           *RNG            // nil seeds from the clock
+	// ChooseReplacements leaves the player to pick who comes in after a faint with
+	// Replace. Otherwise the next healthy party member is sent out automatically.
+	ChooseReplacements bool
+	// ObedienceLevel is the highest level that always obeys, from the player's badges. 0 means everyone obeys.
+	ObedienceLevel int
+}
+
+// Battle is a turn-based state machine. Feed it player actions with Step
+// and it returns the events that happened, without doing any I/O itself.
+type Battle struct {
+	Party      []*BattlePokemon
+	Opponents  []*BattlePokemon // The wild pokemon, or the trainer's team
+	Trainer    *Trainer         // nil in wild battles
+	OpponentAI AI
+	Inventory  *PlayerInventory
+	Turn       int  // The turn being played, starting at 1
+	Double     bool // Two pokemon a side
+	Weather    Weather
+
+	slots          []int // Party index at each of the player's field positions, -1 once nobody is left
+	foeSlots       []int // Opponents index at each of the opponent's field positions
+	weatherTurns   int   // Turns of weather left, 0 while it lasts indefinitely
+	sides          map[string]SideConditions
+	chooseReplace  bool
+	escapeAttempts int
+	pending        []int // Player field positions waiting for Replace
+	trainerPotions int
+	obedienceLevel int
+	participants   map[*BattlePokemon][]*BattlePokemon // Who faced each foe, for sharing XP
+	outcome        BattleOutcome
+	alreadyCaught  bool
+	timeOfDay      string
+	location       string
+	client         *pokeapi.Client
+	rng            *RNG
+	events         []Event // Not yet returned to the caller
+	log            []Event // Everything since the battle started
+}
+
+// NewBattle sends out the first healthy pokemon on each side and returns the opening events
+func NewBattle(cfg BattleConfig) (*Battle, []Event, error) {
+	b := &Battle{
+		Party:          cfg.Party,
+		Trainer:        cfg.Trainer,
+		OpponentAI:     cfg.OpponentAI,
+		Inventory:      cfg.Inventory,
+		Turn:           1,
+		Double:         cfg.Double,
+		participants:   make(map[*BattlePokemon][]*BattlePokemon),
+		sides:          map[string]SideConditions{SidePlayer: {}, SideOpponent: {}},
+		alreadyCaught:  cfg.AlreadyCaught,
+		timeOfDay:      cfg.TimeOfDay,
+		location:       cfg.Location,
+		client:         cfg.Client,
+		rng:            cfg.RNG,
+		obedienceLevel: cfg.ObedienceLevel,
+		chooseReplace:  cfg.ChooseReplacements,
+	}
+	switch {
+	case cfg.Trainer != nil:
+		if len(cfg.TrainerTeam) == 0 {
+			return nil, nil, fmt.Errorf("%s has no pokemon", cfg.Trainer.Title())
+		}
+		b.Opponents = cfg.TrainerTeam
+		b.trainerPotions = cfg.Trainer.Potions
+		if b.OpponentAI == nil {
+			b.OpponentAI = cfg.Trainer.OpponentAI()
 		}
+	case cfg.Wild != nil:
+		if cfg.Double {
+			return nil, nil, errors.New("only trainers can start double battles")
+		}
+		b.Opponents = []*BattlePokemon{cfg.Wild}
+	default:
+		return nil, nil, errors.New("a battle needs a wild pokemon or a trainer")
+	}
+	if b.OpponentAI == nil {
+		b.OpponentAI = RandomAI{}
+	}
+	if b.rng == nil {
+		b.rng = NewRandomRNG()
+	}
+	if b.Inventory == nil {
+		b.Inventory = &PlayerInventory{}
 	}
 
-	if activeMon == nil {
-		fmt.Println("Your entire team is fainted! You assume the fetal position and cry.")
-		return false
+	// Get the first alive pokemon on each side
+	size := 1
+	if b.Double {
+		size = 2
+	}
+	b.slots = firstHealthy(b.Party, size)
+	if b.slots[0] < 0 {
+		return nil, nil, errors.New("your entire team is fainted! You assume the fetal position and cry")
+	}
+	b.foeSlots = firstHealthy(b.Opponents, size)
+	if b.foeSlots[0] < 0 {
+		return nil, nil, errors.New("your opponent has no pokemon able to battle")
+	}
+
+	// Choice items only lock a move for the length of one battle
+	for _, p := range b.Party {
+		p.ChoiceLock = ""
+	}
+	for _, p := range b.Opponents {
+		p.ChoiceLock = ""
+	}
+
+	// Everyone who was sent out against a foe shares its XP
+	b.markParticipants()
+
+	if b.Trainer != nil {
+		b.emit(Event{Kind: EventChallenge, Actor: b.Trainer.Title()})
+		for _, foe := range b.Foes() {
+			b.emit(Event{Kind: EventSwitch, Side: SideOpponent, Actor: b.Trainer.Title(), Target: foe.Nickname})
+		}
+	}
+	b.emit(Event{Kind: EventBattleStart, Actor: b.Name(b.Active()), Target: b.Name(b.Foe())})
+	for _, p := range b.Actives()[1:] {
+		b.emit(Event{Kind: EventSwitch, Side: SidePlayer, Target: b.Name(p)})
+	}
+	if cfg.Weather != WeatherNone {
+		b.setWeather(cfg.Weather, 0, Event{})
+	}
+	for _, p := range append(b.Actives(), b.Foes()...) {
+		b.abilityOnEntry(p)
+	}
+	return b, b.flush(), nil
+}
+
+// firstHealthy fills size field positions from the front of a team
+func firstHealthy(team []*BattlePokemon, size int) []int {
+	slots := make([]int, size)
+	for i := range slots {
+		slots[i] = -1
+	}
+	next := 0
+	for i, p := range team {
+		if next < size && isHealthy(p) {
+			slots[next] = i
+			next++
+		}
+	}
+	return slots
+}
+
+// Active is the player's first pokemon in battle
+func (b *Battle) Active() *BattlePokemon {
+	return b.Actives()[0]
+}
+
+// Actives are the player's pokemon in battle, one per occupied field position
+func (b *Battle) Actives() []*BattlePokemon {
+	return onField(b.Party, b.slots)
+}
+
+// Foe is the opponent's first pokemon in battle
+func (b *Battle) Foe() *BattlePokemon {
+	return b.Foes()[0]
+}
+
+// Foes are the opponent's pokemon in battle
+func (b *Battle) Foes() []*BattlePokemon {
+	return onField(b.Opponents, b.foeSlots)
+}
+
+func onField(team []*BattlePokemon, slots []int) []*BattlePokemon {
+	var field []*BattlePokemon
+	for _, idx := range slots {
+		if idx >= 0 {
+			field = append(field, team[idx])
+		}
+	}
+	return field
+}
+
+// OpponentsLeft counts the opponent's pokemon that can still battle
+func (b *Battle) OpponentsLeft() int {
+	left := 0
+	for _, p := range b.Opponents {
+		if isHealthy(p) {
+			left++
+		}
+	}
+	return left
+}
+
+// Outcome is OutcomeOngoing until the battle ends
+func (b *Battle) Outcome() BattleOutcome {
+	return b.outcome
+}
+
+func (b *Battle) Over() bool {
+	return b.outcome != OutcomeOngoing
+}
+
+// Forfeit ends the battle at once: the player flees the wild, or loses to a trainer
+// they can't run from. It's for when the player can't go on, e.g. input ran out.
+func (b *Battle) Forfeit() []Event {
+	if b.Over() {
+		return nil
+	}
+	if b.Trainer == nil {
+		b.emit(Event{Kind: EventRun})
+		b.outcome = OutcomeFled
+		return b.flush()
+	}
+	b.emit(Event{Kind: EventForfeit, Actor: b.Trainer.Title()})
+	b.outcome = OutcomeLost
+	return b.flush()
+}
+
+// Log is every event of the battle so far, in order
+func (b *Battle) Log() []Event {
+	return b.log
+}
+
+// turnAction is one combatant's queued action for the turn
+type turnAction struct {
+	user   *BattlePokemon
+	action Action
+	first  bool // Switches, items and running happen before any move
+	tie    int  // Random tiebreak between equally fast pokemon
+}
+
+// Step plays one full turn. It takes an action for each of the player's
+// pokemon in battle, in field order; then everyone acts, fastest first.
+// Invalid actions return an error and don't use up the turn.
+func (b *Battle) Step(actions ...Action) ([]Event, error) {
+	if b.Over() {
+		return nil, errors.New("the battle is already over")
+	}
+	if len(b.pending) > 0 {
+		return nil, errors.New("choose a Pokemon to send out first")
+	}
+	actives := b.Actives()
+	if len(actions) != len(actives) {
+		return nil, fmt.Errorf("expected %d actions, one for each pokemon in battle", len(actives))
+	}
+	if err := b.validate(actives, actions); err != nil {
+		return nil, err
 	}
+	defer func() { b.Turn++ }()
 
-	fmt.Printf("\n--- BATTLE STARTED: %s vs Wild %s ---\n", activeMon.Nickname, wildPokemon.Nickname)
+	// --- 1. Everyone picks, the opponent through its AI ---
+	var queue []turnAction
+	for i, p := range actives {
+		queue = append(queue, turnAction{user: p, action: actions[i], first: actions[i].Kind != ActionMove})
+	}
+	queue = append(queue, b.opponentActions()...)
+	for i := range queue {
+		if !queue[i].first {
+			queue[i].tie = b.rng.Intn(1 << 16)
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return b.actsBefore(queue[i], queue[j])
+	})
 
-	for {
-		// --- 1. Pre-Turn Status Check (Burn/Poison damage could go here, usually goes end of turn) ---
+	// --- 2. Resolve in order ---
+	for _, q := range queue {
+		if !isHealthy(q.user) || !b.isOnField(q.user) {
+			continue
+		}
+		if b.side(q.user) == SidePlayer {
+			b.playerAction(q.user, q.action)
+		} else {
+			b.opponentAction(q.user, q.action)
+		}
+		if !b.Over() {
+			b.checkFaints()
+		}
+		if b.Over() {
+			return b.flush(), nil
+		}
+	}
 
-		// --- 2. Player Input ---
-		fmt.Printf("\n%s (Lvl %d): %d/%d HP\n", activeMon.Nickname, activeMon.Level, activeMon.Stats.HP, activeMon.Stats.MaxHP)
-		fmt.Printf("Wild %s (Lvl %d): %d/%d HP\n", wildPokemon.Nickname, wildPokemon.Level, wildPokemon.Stats.HP, wildPokemon.Stats.MaxHP)
+	// --- 3. End of Turn ---
+	b.weatherEndOfTurn()
+	if b.Over() {
+		return b.flush(), nil
+	}
+	for _, p := range append(b.Actives(), b.Foes()...) {
+		b.emitFor(p, heldItemEndOfTurn(p)...)
+	}
+	b.screensEndOfTurn()
+	b.replaceFainted()
+	return b.flush(), nil
+}
 
-		fmt.Println("Choose: (1) Fight  (2) Bag  (3) Pokemon  (4) Run")
-		fmt.Print("> ")
-		scanner.Scan()
-		choice := scanner.Text()
+// actsBefore orders the turn: switches and items, then moves by priority and speed
+func (b *Battle) actsBefore(x, y turnAction) bool {
+	if x.first || y.first {
+		return x.first && !y.first
+	}
+	px, py := x.user.Moves[x.action.Move].Priority, y.user.Moves[y.action.Move].Priority
+	if px != py {
+		return px > py
+	}
+	sx, sy := effectiveSpeed(x.user), effectiveSpeed(y.user)
+	if sx != sy {
+		return sx > sy
+	}
+	return x.tie < y.tie
+}
 
-		turnEnded := false
+func (b *Battle) validate(actives []*BattlePokemon, actions []Action) error {
+	switchingIn := make(map[int]bool)
+	itemsUsed := make(map[string]int)
+	for i, a := range actions {
+		active := actives[i]
+		switch a.Kind {
+		case ActionMove:
+			if a.Move < 0 || a.Move >= len(active.Moves) {
+				return errors.New("invalid move")
+			}
+			move := active.Moves[a.Move]
+			if active.ChoiceLock != "" && active.ChoiceLock != move.Name {
+				return fmt.Errorf("%s's %s only allows the use of %s", active.Nickname, active.HeldItem, active.ChoiceLock)
+			}
+			if a.FoeTarget < 0 || a.FoeTarget >= len(b.foeSlots) {
+				return errors.New("invalid target")
+			}
 
-		switch choice {
-		case "1": // FIGHT
-			turnEnded = handleFightMenu(scanner, activeMon, wildPokemon)
+		case ActionItem:
+			itemsUsed[a.Item]++
+			if IsBall(a.Item) {
+				if b.Trainer != nil {
+					return errors.New("the trainer blocked the ball! Don't be a thief")
+				}
+				if b.Inventory.BallCount(a.Item) < itemsUsed[a.Item] {
+					return fmt.Errorf("you don't have any %ss", BallLabel(a.Item))
+				}
+				continue
+			}
+			if a.Target < 0 || a.Target >= len(b.Party) {
+				return errors.New("invalid target")
+			}
+			target := b.Party[a.Target]
+			switch a.Item {
+			case "potion", "superpotion":
+				if *b.healingItemCount(a.Item) < itemsUsed[a.Item] {
+					return errors.New("you don't have any")
+				}
+				if target.Status == StatusFainted {
+					return errors.New("potions don't work on fainted Pokemon! Use a Revive")
+				}
+			case "revive":
+				if b.Inventory.Revives < itemsUsed[a.Item] {
+					return errors.New("you don't have any Revives")
+				}
+				if target.Status != StatusFainted {
+					return errors.New("that Pokemon is already conscious")
+				}
+			default:
+				return fmt.Errorf("you can't use %s here", a.Item)
+			}
 
-		case "2": // BAG (Catching happens here)
-			caught, usedTurn := handleBagMenu(scanner, inventory, wildPokemon, activeMon)
-			if caught {
-				return true // Battle ends, pokemon caught
+		case ActionSwitch:
+			if a.Target < 0 || a.Target >= len(b.Party) {
+				return errors.New("invalid selection")
 			}
-			turnEnded = usedTurn
-
-		case "3": // POKEMON (Switching)
-			newMon := handleSwitchMenu(scanner, party)
-			if newMon != nil {
-				activeMon = newMon
-				fmt.Printf("Go! %s!\n", activeMon.Nickname)
-				turnEnded = true
+			target := b.Party[a.Target]
+			if b.isOnField(target) || switchingIn[a.Target] {
+				return fmt.Errorf("%s is already in battle", target.Nickname)
 			}
+			if !isHealthy(target) {
+				return fmt.Errorf("%s has no energy left to battle", target.Nickname)
+			}
+			switchingIn[a.Target] = true
 
-		case "4": // RUN
-			// Run formula: Speed check
-			if activeMon.Stats.Speed >= wildPokemon.Stats.Speed || rand.Intn(100) < 50 {
-				fmt.Println("Got away safely!")
-				return false
+		case ActionRun:
+			if b.Trainer != nil {
+				return errors.New("there's no running from a trainer battle")
 			}
-			fmt.Println("Can't escape!")
-			turnEnded = true
+
+		default:
+			return errors.New("unknown action")
 		}
+	}
+	return nil
+}
 
-		if !turnEnded {
-			continue
+// aiView is what an AI sees when choosing for self
+func (b *Battle) aiView(self *BattlePokemon) AIView {
+	view := AIView{Self: self}
+	allies, foes := b.Actives(), b.Foes()
+	if b.side(self) == SideOpponent {
+		allies, foes = foes, allies
+	}
+	for _, p := range foes {
+		if isHealthy(p) {
+			view.Foes = append(view.Foes, p)
 		}
+	}
+	for _, p := range allies {
+		if p != self && isHealthy(p) {
+			view.Allies = append(view.Allies, p)
+		}
+	}
+	if len(view.Foes) > 0 {
+		view.Foe = view.Foes[0]
+	}
+
+	switch {
+	case b.side(self) == SidePlayer:
+		view.Team = b.Party
+		view.Potions = b.Inventory.Potions
+	case b.Trainer != nil:
+		// Wild pokemon have no team or items, so they always attack
+		view.Team = b.Opponents
+		view.Potions = b.trainerPotions
+	}
+	return view
+}
 
-		// --- 3. Enemy Turn ---
-		if wildPokemon.Stats.HP > 0 {
-			// Simple AI: Random move
-			move := wildPokemon.Moves[rand.Intn(len(wildPokemon.Moves))]
-			performMove(wildPokemon, activeMon, &move)
+// aiAction turns an AI's choice into an action, falling back to a random move
+// when the AI asks for something it can't do
+func (b *Battle) aiAction(self *BattlePokemon, ai AI, switchingIn map[int]bool, potions *int) Action {
+	view := b.aiView(self)
+	team := b.Opponents
+	if b.side(self) == SidePlayer {
+		team = b.Party
+	}
+	choice := ai.Choose(view, b.rng)
 
-			if activeMon.Stats.HP <= 0 {
-				activeMon.Stats.HP = 0
-				activeMon.Status = StatusFainted
-				fmt.Printf("%s fainted!\n", activeMon.Nickname)
+	switch {
+	case choice.Kind == AISwitch && len(view.Team) > 0 && choice.Switch >= 0 && choice.Switch < len(team) &&
+		isHealthy(team[choice.Switch]) && !b.isOnField(team[choice.Switch]) && !switchingIn[choice.Switch]:
+		switchingIn[choice.Switch] = true
+		return Action{Kind: ActionSwitch, Target: choice.Switch}
 
-				// Force switch or lose
-				if !forceSwitch(scanner, party, &activeMon) {
-					fmt.Println("You blacked out...")
-					return false
-				}
+	case choice.Kind == AIUsePotion && *potions > 0:
+		*potions--
+		return Action{Kind: ActionItem, Item: "potion", Target: indexOf(team, self)}
+	}
+
+	if choice.Kind != AIUseMove || choice.Move < 0 || choice.Move >= len(self.Moves) {
+		choice = RandomAI{}.Choose(view, b.rng)
+	}
+	if self.ChoiceLock != "" {
+		for i := range self.Moves {
+			if self.Moves[i].Name == self.ChoiceLock {
+				choice.Move = i
 			}
+		}
+	}
+
+	// The AI aims at one of the foes it was shown; find where that foe stands
+	target := 0
+	if choice.Target >= 0 && choice.Target < len(view.Foes) {
+		target = b.fieldPosition(view.Foes[choice.Target])
+	}
+	return Action{Kind: ActionMove, Move: choice.Move, FoeTarget: target}
+}
+
+func (b *Battle) opponentActions() []turnAction {
+	var queue []turnAction
+	switchingIn := make(map[int]bool)
+	potions := b.trainerPotions
+	for _, foe := range b.Foes() {
+		if !isHealthy(foe) {
+			continue
+		}
+		a := b.aiAction(foe, b.OpponentAI, switchingIn, &potions)
+		queue = append(queue, turnAction{user: foe, action: a, first: a.Kind != ActionMove})
+	}
+	return queue
+}
+
+func (b *Battle) playerAction(active *BattlePokemon, a Action) {
+	switch a.Kind {
+	case ActionMove:
+		if !b.obeys(active) {
+			b.emit(Event{Kind: EventDisobey, Actor: b.Name(active)})
+			return
+		}
+		move := &active.Moves[a.Move]
+		if isChoiceItem(active.HeldItem) {
+			active.ChoiceLock = move.Name
+		}
+		b.performMove(active, move, a.FoeTarget)
+
+	case ActionItem:
+		if IsBall(a.Item) {
+			b.throwBall(a.Item)
 		} else {
-			// Enemy Fainted
-			fmt.Printf("Wild %s fainted!\n", wildPokemon.Nickname)
+			b.useHealingItem(a.Item, b.Party[a.Target])
+		}
+
+	case ActionSwitch:
+		active.ChoiceLock = ""
+		b.sendOut(b.fieldPosition(active), a.Target)
+
+	case ActionRun:
+		b.escapeAttempts++
+		if alwaysEscapes(active) {
+			b.emit(Event{Kind: EventRun, Actor: b.Name(active), Item: active.HeldItem, Ability: active.Ability})
+			b.outcome = OutcomeFled
+			return
+		}
+		odds := EscapeOdds(effectiveSpeed(active), effectiveSpeed(b.Foe()), b.escapeAttempts)
+		if odds >= EscapeCertain || b.rng.Intn(EscapeCertain) < odds {
+			b.emit(Event{Kind: EventRun})
+			b.outcome = OutcomeFled
+			return
+		}
+		b.emit(Event{Kind: EventRunFailed, Amount: b.escapeAttempts})
+	}
+}
+
+func (b *Battle) opponentAction(foe *BattlePokemon, a Action) {
+	switch a.Kind {
+	case ActionSwitch:
+		foe.ChoiceLock = ""
+		b.foeSendOut(b.fieldPosition(foe), a.Target)
+
+	case ActionItem:
+		b.trainerPotions--
+		before := foe.Stats.HP
+		foe.Stats.HP = min(foe.Stats.HP+20, foe.Stats.MaxHP)
+		b.emit(Event{Kind: EventHeal, Side: SideOpponent, Actor: b.Trainer.Title(), Target: b.Name(foe), Item: "potion", Amount: foe.Stats.HP - before, HP: foe.Stats.HP, MaxHP: foe.Stats.MaxHP})
+
+	default:
+		move := &foe.Moves[a.Move]
+		if isChoiceItem(foe.HeldItem) {
+			foe.ChoiceLock = move.Name
+		}
+		b.performMove(foe, move, a.FoeTarget)
+	}
+}
+
+// sendOut puts a party member into one of the player's field positions
+func (b *Battle) sendOut(pos, idx int) {
+	b.slots[pos] = idx
+	b.markParticipants()
+	b.emit(Event{Kind: EventSwitch, Side: SidePlayer, Target: b.Name(b.Party[idx])})
+	b.onEntry(b.Party[idx])
+}
+
+// foeSendOut brings in another of the trainer's pokemon
+func (b *Battle) foeSendOut(pos, idx int) {
+	b.foeSlots[pos] = idx
+	b.markParticipants()
+	b.emit(Event{Kind: EventSwitch, Side: SideOpponent, Actor: b.Trainer.Title(), Target: b.Opponents[idx].Nickname})
+	b.onEntry(b.Opponents[idx])
+}
+
+// markParticipants records which of the player's pokemon have faced each foe on the field
+func (b *Battle) markParticipants() {
+	for _, foe := range b.Foes() {
+		if !isHealthy(foe) {
+			continue
+		}
+		for _, p := range b.Actives() {
+			if isHealthy(p) {
+				b.participants[foe] = addParticipant(b.participants[foe], p)
+			}
+		}
+	}
+}
+
+// checkFaints handles every pokemon knocked out by the last action, and ends the battle once a side is out
+func (b *Battle) checkFaints() {
+	for _, p := range b.Actives() {
+		if p.Stats.HP > 0 || p.Status == StatusFainted {
+			continue
+		}
+		p.Stats.HP = 0
+		p.Status = StatusFainted
+		p.AdjustFriendship(FriendshipFainted)
+		b.emit(Event{Kind: EventFaint, Side: SidePlayer, Target: b.Name(p)})
+	}
+
+	for _, foe := range b.Foes() {
+		if foe.Stats.HP > 0 || foe.Status == StatusFainted {
+			continue
+		}
+		foe.Stats.HP = 0
+		foe.Status = StatusFainted
+		b.emit(Event{Kind: EventFaint, Side: SideOpponent, Target: b.Name(foe)})
+
+		for _, p := range b.participants[foe] {
+			if p.Status != StatusFainted {
+				p.AdjustFriendship(FriendshipBattle)
+			}
+		}
+		if b.client != nil {
+			b.distributeXP(foe)
+		}
+	}
+
+	if b.OpponentsLeft() == 0 {
+		b.win()
+		return
+	}
+	for _, p := range b.Party {
+		if isHealthy(p) {
+			return
+		}
+	}
+	b.emit(Event{Kind: EventBlackout})
+	b.outcome = OutcomeLost
+}
+
+// replaceFainted fills the field positions of fainted pokemon at the end of the turn
+func (b *Battle) replaceFainted() {
+	// Hazards can knock a replacement out as it comes in, so keep going until the field is healthy
+	for !b.Over() && b.replaceOnce() {
+	}
+	b.compactSlots()
+}
+
+// EscapeAttempts counts how many times the player has tried to run this battle
+func (b *Battle) EscapeAttempts() int {
+	return b.escapeAttempts
+}
+
+// NeedsReplacement lists the player's field positions whose pokemon fainted
+// and are waiting for Replace. It's always empty without ChooseReplacements.
+func (b *Battle) NeedsReplacement() []int {
+	return b.pending
+}
+
+// DefaultReplacement is the first healthy party member on the bench, or -1 if there's nobody left
+func (b *Battle) DefaultReplacement() int {
+	for i, p := range b.Party {
+		if isHealthy(p) && !b.isOnField(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Replace sends out party member idx in place of the fainted pokemon at field position pos
+func (b *Battle) Replace(pos, idx int) ([]Event, error) {
+	if !b.isPending(pos) {
+		return nil, errors.New("that Pokemon doesn't need replacing")
+	}
+	if idx < 0 || idx >= len(b.Party) {
+		return nil, errors.New("invalid selection")
+	}
+	target := b.Party[idx]
+	if b.isOnField(target) {
+		return nil, fmt.Errorf("%s is already in battle", target.Nickname)
+	}
+	if !isHealthy(target) {
+		return nil, fmt.Errorf("%s has no energy left to battle", target.Nickname)
+	}
 
-			// Calculate reward based on level
-			goldReward := wildPokemon.Level*50 + rand.Intn(30)
-			inventory.Money += goldReward
-			fmt.Printf("You received ₽%d for winning!\n", goldReward)
+	for i, p := range b.pending {
+		if p == pos {
+			b.pending = append(b.pending[:i], b.pending[i+1:]...)
+			break
+		}
+	}
+	b.sendOut(pos, idx)
+	b.checkFaints()
+	// Hazards might have knocked the replacement straight out
+	b.replaceFainted()
+	return b.flush(), nil
+}
+
+// benchedHealthy counts the party members who could still be sent out
+func (b *Battle) benchedHealthy() int {
+	n := 0
+	for _, p := range b.Party {
+		if isHealthy(p) && !b.isOnField(p) {
+			n++
+		}
+	}
+	return n
+}
+
+func (b *Battle) isPending(pos int) bool {
+	for _, p := range b.pending {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceOnce sends in one replacement for each fainted pokemon, reporting whether anyone came in
+func (b *Battle) replaceOnce() bool {
+	sent := false
+	for pos, idx := range b.slots {
+		if idx < 0 || isHealthy(b.Party[idx]) || b.isPending(pos) {
+			continue
+		}
+		next := b.DefaultReplacement()
+		switch {
+		case next < 0 || b.chooseReplace && b.benchedHealthy() <= len(b.pending):
+			b.slots[pos] = -1
+		case b.chooseReplace:
+			// The fainted pokemon keeps its place until the player picks
+			b.pending = append(b.pending, pos)
+		default:
+			// Force switch to the next pokemon that can battle
+			b.sendOut(pos, next)
+			sent = true
+		}
+	}
+	for pos, idx := range b.foeSlots {
+		if idx < 0 || isHealthy(b.Opponents[idx]) {
+			continue
+		}
+		b.foeSlots[pos] = -1
+		for i, p := range b.Opponents {
+			if isHealthy(p) && !b.isOnField(p) {
+				b.foeSendOut(pos, i)
+				sent = true
+				break
+			}
+		}
+	}
+	if sent {
+		b.checkFaints()
+	}
+	return sent
+}
+
+// compactSlots keeps a lone survivor in the first field position, so
+// Active and Foe always have someone to return
+func (b *Battle) compactSlots() {
+	for _, slots := range [][]int{b.slots, b.foeSlots} {
+		if len(slots) > 1 && slots[0] < 0 {
+			slots[0], slots[1] = slots[1], slots[0]
+		}
+	}
+}
+
+func (b *Battle) win() {
+	var reward int
+	if b.Trainer != nil {
+		b.emit(Event{Kind: EventTrainerDefeated, Actor: b.Trainer.Title()})
+		reward = b.Trainer.PrizeMoney()
+	} else {
+		// Calculate reward based on level
+		reward = b.Foe().Level*50 + b.rng.Intn(30)
+	}
+	b.Inventory.Money += reward
+	b.emit(Event{Kind: EventMoney, Amount: reward})
+	b.outcome = OutcomeWon
+}
+
+// obeys rolls whether a pokemon above the obedience level follows orders.
+// The further above it is, the more likely it loafs around.
+func (b *Battle) obeys(p *BattlePokemon) bool {
+	if b.obedienceLevel <= 0 || p.Level <= b.obedienceLevel {
+		return true
+	}
+	return b.rng.Intn(p.Level+b.obedienceLevel) < b.obedienceLevel
+}
+
+// canAct handles statuses that can stop a pokemon from moving
+func (b *Battle) canAct(p *BattlePokemon) bool {
+	switch p.Status {
+	case StatusSleep:
+		if b.rng.Intn(3) == 0 {
+			p.Status = StatusNone
+			b.emit(Event{Kind: EventStatusEnd, Target: b.Name(p), Status: StatusSleep})
+			return true
+		}
+		b.emit(Event{Kind: EventCantMove, Actor: b.Name(p), Status: StatusSleep})
+		return false
+	case StatusFreeze:
+		if b.rng.Intn(5) == 0 {
+			p.Status = StatusNone
+			b.emit(Event{Kind: EventStatusEnd, Target: b.Name(p), Status: StatusFreeze})
+			return true
+		}
+		b.emit(Event{Kind: EventCantMove, Actor: b.Name(p), Status: StatusFreeze})
+		return false
+	}
+	return true
+}
+
+// moveTargets picks who a move hits. Single-target moves aimed at a
+// pokemon that has since fainted hit its partner instead.
+func (b *Battle) moveTargets(attacker *BattlePokemon, move *Move, pos int) []*BattlePokemon {
+	allies, foes := b.Actives(), b.Foes()
+	slots, team := b.foeSlots, b.Opponents
+	if b.side(attacker) == SideOpponent {
+		allies, foes = foes, allies
+		slots, team = b.slots, b.Party
+	}
+
+	var healthy []*BattlePokemon
+	for _, p := range foes {
+		if isHealthy(p) {
+			healthy = append(healthy, p)
+		}
+	}
 
-			distributeXP(activeMon, wildPokemon, client)
-			return true // Win
+	switch {
+	case !move.HitsOpponents() || len(healthy) == 0:
+		return nil
+	case move.IsSpread():
+		if move.HitsAlly() {
+			for _, p := range allies {
+				if p != attacker && isHealthy(p) {
+					healthy = append(healthy, p)
+				}
+			}
 		}
+		return healthy
+	case move.Target == TargetRandomOpponent:
+		return []*BattlePokemon{healthy[b.rng.Intn(len(healthy))]}
 	}
+
+	if pos >= 0 && pos < len(slots) && slots[pos] >= 0 && isHealthy(team[slots[pos]]) {
+		return []*BattlePokemon{team[slots[pos]]}
+	}
+	return healthy[:1]
 }
 
-func performMove(attacker, defender *BattlePokemon, move *Move) {
+func (b *Battle) performMove(attacker *BattlePokemon, move *Move, pos int) {
+	if !b.canAct(attacker) {
+		return
+	}
 	move.CurrentPP--
 	if move.CurrentPP < 0 {
 		move.CurrentPP = 0
 	}
-	fmt.Printf("%s used %s!\n", attacker.Nickname, move.Name)
 
-	// Accuracy Check
-	if rand.Intn(100) > move.Accuracy {
-		fmt.Println("...but it missed!")
+	if w, ok := weatherMoves[move.Name]; ok {
+		b.emit(Event{Kind: EventMove, Side: b.side(attacker), Actor: b.Name(attacker), Move: move.Name})
+		if !b.setWeather(w, DefaultWeatherTurns, Event{}) {
+			b.emit(Event{Kind: EventFailed})
+		}
 		return
 	}
 
-	// Damage Calc
-	// ((2 * Level / 5 + 2) * Power * A / D) / 50 + 2
-	atk := float64(attacker.Stats.Attack)
-	def := float64(defender.Stats.Defense)
-	damage := (((2.0*float64(attacker.Level)/5.0 + 2.0) * float64(move.Power) * atk / def) / 50.0) + 2.0
+	if IsSideCondition(move.Name) {
+		b.emit(Event{Kind: EventMove, Side: b.side(attacker), Actor: b.Name(attacker), Move: move.Name})
+		side := b.side(attacker)
+		if !IsScreen(move.Name) {
+			side = otherSide(side)
+		}
+		if !b.addSideCondition(side, move.Name) {
+			b.emit(Event{Kind: EventFailed})
+		}
+		return
+	}
+
+	targets := b.moveTargets(attacker, move, pos)
+	event := Event{Kind: EventMove, Side: b.side(attacker), Actor: b.Name(attacker), Move: move.Name}
+	if len(targets) > 0 {
+		event.Target = b.Name(targets[0])
+	}
+	b.emit(event)
+
+	if len(targets) == 0 {
+		if move.HitsOpponents() {
+			b.emit(Event{Kind: EventNoTarget, Actor: b.Name(attacker)})
+		}
+		return
+	}
+	spread := move.IsSpread() && len(targets) > 1
+	for _, defender := range targets {
+		b.hit(attacker, defender, move, spread)
+	}
+}
+
+// hit resolves a move against one target
+func (b *Battle) hit(attacker, defender *BattlePokemon, move *Move, spread bool) {
+	// Accuracy Check
+	if accuracy := WeatherAccuracy(b.Weather, move); accuracy > 0 && b.rng.Intn(100) > accuracy {
+		b.emit(Event{Kind: EventMiss, Actor: b.Name(attacker), Target: b.Name(defender)})
+		return
+	}
 
-	// Apply Type Effectiveness (Simplified)
-	// You should integrate the 'types.go' logic from previous step here
+	// Status moves go straight to their effect
+	if movePower(attacker, move) <= 0 {
+		b.applyStatus(defender, move)
+		return
+	}
 
-	finalDamage := int(damage)
-	if finalDamage < 1 {
-		finalDamage = 1
+	// Damage Calc
+	effectiveness := GetTypeEffectiveness(move.Type, defender.TypeNames())
+	if effectiveness == 0 {
+		b.emit(Event{Kind: EventEffectiveness, Target: b.Name(defender), Multiplier: effectiveness})
+		return
+	}
+	crit := b.rng.Intn(CritChance) == 0
+	finalDamage := CalculateDamage(attacker, defender, move, crit, rollDamage(b.rng))
+	if w := WeatherMultiplier(b.Weather, move.Type); w != 1 {
+		finalDamage = max(int(float64(finalDamage)*w), 1)
+	}
+	if !crit && b.screened(defender, move) {
+		screen := ScreenMultiplier
+		if b.Double {
+			screen = DoubleScreenMultiplier
+		}
+		finalDamage = max(int(float64(finalDamage)*screen), 1)
+	}
+	if spread {
+		finalDamage = max(int(float64(finalDamage)*SpreadMultiplier), 1)
 	}
 
 	defender.Stats.HP -= finalDamage
 	if defender.Stats.HP < 0 {
 		defender.Stats.HP = 0
 	}
-	fmt.Printf("Dealt %d damage.\n", finalDamage)
+	b.emit(Event{Kind: EventDamage, Side: b.side(defender), Target: b.Name(defender), Amount: finalDamage, HP: defender.Stats.HP, MaxHP: defender.Stats.MaxHP})
+	if crit {
+		b.emit(Event{Kind: EventCrit, Actor: b.Name(attacker)})
+	}
+	if effectiveness != 1 {
+		b.emit(Event{Kind: EventEffectiveness, Target: b.Name(defender), Multiplier: effectiveness})
+	}
+	b.emitFor(defender, checkHeldBerry(defender)...)
+	b.applyStatus(defender, move)
+}
 
-	// Apply Status
-	if move.StatusEffect != StatusNone && defender.Status == StatusNone && rand.Intn(100) < 30 {
+// applyStatus gives a move's status condition a chance to stick
+func (b *Battle) applyStatus(defender *BattlePokemon, move *Move) {
+	if move.StatusEffect != StatusNone && defender.Status == StatusNone && defender.Stats.HP > 0 && b.rng.Intn(100) < 30 {
 		defender.Status = move.StatusEffect
-		fmt.Printf("%s was afflicted with status %d!\n", defender.Nickname, move.StatusEffect)
-	}
-}
-
-func handleBagMenu(scanner *bufio.Scanner, inv *PlayerInventory, wild *BattlePokemon, playerMon *BattlePokemon) (bool, bool) {
-	fmt.Println("\n--- BAG ---")
-	fmt.Println("1. Pokeballs")
-	fmt.Println("2. Healing Items")
-	fmt.Println("3. Cancel")
-
-	fmt.Print("Select category > ")
-	scanner.Scan()
-	category := scanner.Text()
-
-	switch category {
-	case "1": // POKEBALL SUB-MENU
-		fmt.Println("\n--- POKEBALLS ---")
-		fmt.Printf("1. Pokeball (x%d)\n", inv.Pokeballs)
-		fmt.Printf("2. Greatball (x%d)\n", inv.Greatballs)
-		fmt.Printf("3. Ultraball (x%d)\n", inv.Ultraballs)
-		fmt.Println("4. Back")
-
-		fmt.Print("Select ball > ")
-		scanner.Scan()
-		ballChoice := scanner.Text()
-
-		var multiplier float64
-		var ballName string
-		count := 0
-
-		switch ballChoice {
-		case "1":
-			multiplier, ballName, count = 1.0, "Pokeball", inv.Pokeballs
-		case "2":
-			multiplier, ballName, count = 1.5, "Greatball", inv.Greatballs
-		case "3":
-			multiplier, ballName, count = 2.0, "Ultraball", inv.Ultraballs
-		default:
-			return false, false // Go back to main battle menu
+		b.emit(Event{Kind: EventStatus, Target: b.Name(defender), Status: move.StatusEffect})
+		b.emitFor(defender, checkHeldBerry(defender)...)
+	}
+}
+
+// WeatherTurnsLeft is how long the weather lasts, 0 when it won't end by itself
+func (b *Battle) WeatherTurnsLeft() int {
+	return b.weatherTurns
+}
+
+// setWeather replaces the weather for turns turns (0 for good), narrating with
+// the given event. Weather that's already going can't be started again.
+func (b *Battle) setWeather(w Weather, turns int, cause Event) bool {
+	if b.Weather == w {
+		return false
+	}
+	b.Weather = w
+	b.weatherTurns = turns
+	cause.Kind = EventWeatherStart
+	cause.Weather = w
+	b.emit(cause)
+	return true
+}
+
+// abilityOnEntry triggers abilities that act when a pokemon is sent out
+func (b *Battle) abilityOnEntry(p *BattlePokemon) {
+	if w, ok := weatherAbilities[p.Ability]; ok {
+		b.setWeather(w, DefaultWeatherTurns, Event{Actor: b.Name(p), Ability: p.Ability})
+	}
+}
+
+// weatherEndOfTurn counts the weather down and deals its chip damage
+func (b *Battle) weatherEndOfTurn() {
+	if b.Weather == WeatherNone {
+		return
+	}
+	if b.weatherTurns > 0 {
+		b.weatherTurns--
+		if b.weatherTurns == 0 {
+			b.emit(Event{Kind: EventWeatherEnd, Weather: b.Weather})
+			b.Weather = WeatherNone
+			return
 		}
+	}
+	b.emit(Event{Kind: EventWeather, Weather: b.Weather})
 
-		if count <= 0 {
-			fmt.Printf("You don't have any %ss!\n", ballName)
-			return false, false
+	for _, p := range append(b.Actives(), b.Foes()...) {
+		if !isHealthy(p) {
+			continue
+		}
+		if chip := WeatherChipDamage(b.Weather, p); chip > 0 {
+			p.Stats.HP = max(p.Stats.HP-chip, 0)
+			b.emit(Event{Kind: EventWeatherDamage, Side: b.side(p), Target: b.Name(p), Weather: b.Weather, Amount: chip, HP: p.Stats.HP, MaxHP: p.Stats.MaxHP})
 		}
+	}
+	b.checkFaints()
+}
 
-		// Deduct item
-		switch ballChoice {
-		case "1":
-			inv.Pokeballs--
-		case "2":
-			inv.Greatballs--
-		default:
-			inv.Ultraballs--
+// SideConditions are the hazards and screens on a side, SidePlayer or SideOpponent
+func (b *Battle) SideConditions(side string) SideConditions {
+	return b.sides[side]
+}
+
+// addSideCondition lays a hazard or puts up a screen, failing once it can't stack any higher
+func (b *Battle) addSideCondition(side, condition string) bool {
+	conditions := b.sides[side]
+	if conditions[condition] >= sideConditionMaxLayers[condition] {
+		return false
+	}
+	if IsScreen(condition) {
+		conditions[condition] = ScreenTurns
+	} else {
+		conditions[condition]++
+	}
+	b.emit(Event{Kind: EventSideCondition, Side: side, Move: condition, Amount: conditions[condition]})
+	return true
+}
+
+// onEntry applies hazards to a pokemon that was just sent out, then its ability
+func (b *Battle) onEntry(p *BattlePokemon) {
+	side := b.side(p)
+	conditions := b.sides[side]
+	if damage := HazardDamage(conditions, p); damage > 0 {
+		p.Stats.HP = max(p.Stats.HP-damage, 0)
+		b.emit(Event{Kind: EventHazardDamage, Side: side, Target: b.Name(p), Amount: damage, HP: p.Stats.HP, MaxHP: p.Stats.MaxHP})
+	}
+	if conditions[ConditionToxicSpikes] > 0 && grounded(p) && p.Stats.HP > 0 {
+		switch {
+		case p.HasType("poison"):
+			// Grounded poison types soak the spikes up
+			delete(conditions, ConditionToxicSpikes)
+			b.emit(Event{Kind: EventSideConditionEnd, Side: side, Move: ConditionToxicSpikes, Target: b.Name(p)})
+		case p.Status == StatusNone && !p.HasType("steel"):
+			p.Status = StatusPoison
+			b.emit(Event{Kind: EventStatus, Target: b.Name(p), Status: StatusPoison})
+			b.emitFor(p, checkHeldBerry(p)...)
 		}
+	}
+	if p.Stats.HP > 0 {
+		b.abilityOnEntry(p)
+	}
+}
 
-		fmt.Printf("You threw a %s!\n", ballName)
+// screened reports whether a screen on the defender's side weakens the move
+func (b *Battle) screened(defender *BattlePokemon, move *Move) bool {
+	conditions := b.sides[b.side(defender)]
+	if move.IsSpecial() {
+		return conditions[ConditionLightScreen] > 0
+	}
+	return conditions[ConditionReflect] > 0
+}
 
-		// Catch Formula with Multiplier
-		chance := float64(((3*wild.Stats.MaxHP)-(2*wild.Stats.HP))*100) / float64(3*wild.Stats.MaxHP)
-		if wild.Status != StatusNone {
-			chance += 10
+// screensEndOfTurn wears Reflect and Light Screen down
+func (b *Battle) screensEndOfTurn() {
+	for _, side := range []string{SidePlayer, SideOpponent} {
+		for _, screen := range []string{ConditionReflect, ConditionLightScreen} {
+			conditions := b.sides[side]
+			if conditions[screen] == 0 {
+				continue
+			}
+			conditions[screen]--
+			if conditions[screen] == 0 {
+				delete(conditions, screen)
+				b.emit(Event{Kind: EventSideConditionEnd, Side: side, Move: screen})
+			}
 		}
+	}
+}
 
-		finalChance := chance * multiplier
+func otherSide(side string) string {
+	if side == SidePlayer {
+		return SideOpponent
+	}
+	return SidePlayer
+}
 
-		if rand.Intn(100) < int(finalChance) {
-			fmt.Printf("Gotcha! The %s was caught!\n", wild.Base.Name)
-			return true, true
+// isOnField reports whether a pokemon holds one of the field positions
+func (b *Battle) isOnField(p *BattlePokemon) bool {
+	return b.fieldPosition(p) >= 0
+}
+
+// fieldPosition is where a pokemon stands on its side, or -1 when it's benched
+func (b *Battle) fieldPosition(p *BattlePokemon) int {
+	slots, team := b.slots, b.Party
+	if b.side(p) == SideOpponent {
+		slots, team = b.foeSlots, b.Opponents
+	}
+	for pos, idx := range slots {
+		if idx >= 0 && team[idx] == p {
+			return pos
 		}
-		fmt.Println("It broke free!")
-		return false, true
+	}
+	return -1
+}
 
-	case "2": // HEALING SUB-MENU
-		fmt.Println("\n--- HEALING ---")
-		fmt.Printf("1. Potion (x%d) [+20 HP]\n", inv.Potions)
-		fmt.Printf("2. Super Potion (x%d) [+50 HP]\n", inv.SuperPotions)
-		fmt.Printf("3. Revive (x%d) [Restores Fainted]\n", inv.Revives)
-		fmt.Println("4. Back")
+func indexOf(team []*BattlePokemon, p *BattlePokemon) int {
+	for i, q := range team {
+		if q == p {
+			return i
+		}
+	}
+	return -1
+}
 
-		fmt.Print("Select item > ")
-		scanner.Scan()
-		itemChoice := scanner.Text()
+func (b *Battle) throwBall(name string) {
+	ball, _ := FindBall(name)
 
-		switch itemChoice {
-		case "1", "2":
-			healAmt := 20
-			itemPtr := &inv.Potions
-			if itemChoice == "2" {
-				healAmt = 50
-				itemPtr = &inv.SuperPotions
-			}
+	// Deduct item
+	b.Inventory.AddBall(ball.Name, -1)
+	b.emit(Event{Kind: EventBallThrown, Item: ball.Name})
 
-			if *itemPtr <= 0 {
-				fmt.Println("You don't have any!")
-				return false, false
-			}
-			if playerMon.Status == StatusFainted {
-				fmt.Println("Potions don't work on fainted Pokemon! Use a Revive.")
-				return false, false
-			}
+	// Mainline catch formula: species capture rate, HP, ball and status
+	catchCtx := CatchContext{
+		Wild:          b.Foe(),
+		Active:        b.Active(),
+		Turn:          b.Turn,
+		TimeOfDay:     b.timeOfDay,
+		AlreadyCaught: b.alreadyCaught,
+	}
+	result := AttemptCatch(b.Foe(), ball.Bonus(catchCtx), ball.Name == "masterball", b.rng)
+	for i := 0; i < result.Shakes; i++ {
+		b.emit(Event{Kind: EventShake})
+	}
 
-			*itemPtr--
-			playerMon.Stats.HP += healAmt
-			if playerMon.Stats.HP > playerMon.Stats.MaxHP {
-				playerMon.Stats.HP = playerMon.Stats.MaxHP
-			}
-			fmt.Printf("Used Potion! %s's HP is now %d/%d\n", playerMon.Nickname, playerMon.Stats.HP, playerMon.Stats.MaxHP)
-			return false, true
+	if !result.Caught {
+		b.emit(Event{Kind: EventBreakFree, Target: b.Name(b.Foe()), Amount: result.Shakes})
+		return
+	}
 
-		case "3": // REVIVE LOGIC
-			if inv.Revives <= 0 {
-				fmt.Println("You don't have any Revives!")
-				return false, false
-			}
-			if playerMon.Status != StatusFainted {
-				fmt.Println("That Pokemon is already conscious!")
-				return false, false
-			}
+	b.Foe().CaughtBall = ball.Name
+	if ball.Name == "heal-ball" {
+		b.Foe().HealFull()
+	}
+	b.emit(Event{Kind: EventCaught, Target: b.Foe().Base.Name, Item: ball.Name})
+	b.outcome = OutcomeCaught
+}
+
+func (b *Battle) healingItemCount(item string) *int {
+	if item == "superpotion" {
+		return &b.Inventory.SuperPotions
+	}
+	return &b.Inventory.Potions
+}
+
+func (b *Battle) useHealingItem(item string, target *BattlePokemon) {
+	if item == "revive" {
+		b.Inventory.Revives--
+		target.AdjustFriendship(FriendshipHealed)
+		target.Status = StatusNone
+		target.Stats.HP = target.Stats.MaxHP / 2
+		b.emit(Event{Kind: EventRevive, Target: b.Name(target), HP: target.Stats.HP, MaxHP: target.Stats.MaxHP})
+		return
+	}
+
+	healAmt := 20
+	if item == "superpotion" {
+		healAmt = 50
+	}
+	*b.healingItemCount(item)--
+	target.AdjustFriendship(FriendshipHealed)
 
-			inv.Revives--
-			playerMon.Status = StatusNone
-			playerMon.Stats.HP = playerMon.Stats.MaxHP / 2
-			fmt.Printf("%s was revived to half HP!\n", playerMon.Nickname)
-			return false, true
+	before := target.Stats.HP
+	target.Stats.HP += healAmt
+	if target.Stats.HP > target.Stats.MaxHP {
+		target.Stats.HP = target.Stats.MaxHP
+	}
+	b.emit(Event{Kind: EventHeal, Target: b.Name(target), Item: item, Amount: target.Stats.HP - before, HP: target.Stats.HP, MaxHP: target.Stats.MaxHP})
+}
+
+func (b *Battle) distributeXP(loser *BattlePokemon) {
+	shares := ShareExperience(b.Party, b.participants[loser], loser, b.Inventory.ExpShareOn)
+	if b.Trainer != nil {
+		// Trainers' pokemon are worth half as much again
+		for p, xp := range shares {
+			shares[p] = xp * 3 / 2
 		}
 	}
+	ctx := EvolutionContext{
+		Inventory: b.Inventory,
+		Party:     b.Party,
+		Location:  b.location,
+		TimeOfDay: b.timeOfDay,
+	}
 
-	return false, false
+	// Walk the party so the messages come out in team order
+	for _, p := range b.Party {
+		if xpGain, ok := shares[p]; ok {
+			b.gainXP(p, xpGain, ctx)
+		}
+	}
 }
 
-func distributeXP(winner *BattlePokemon, loser *BattlePokemon, client pokeapi.Client) {
-	xpGain := (loser.Base.BaseExperience * loser.Level) / 7
+func (b *Battle) gainXP(winner *BattlePokemon, xpGain int, ctx EvolutionContext) {
+	curve := winner.EnsureGrowthRate(*b.client)
+	if winner.Level >= MaxLevel {
+		return
+	}
+
 	winner.XP += xpGain
-	fmt.Printf("%s gained %d XP!\n", winner.Nickname, xpGain)
+	b.emit(Event{Kind: EventXP, Target: b.Name(winner), Amount: xpGain})
 
-	if winner.XP >= winner.NextLevelXP {
-		winner.Level++
-		winner.XP -= winner.NextLevelXP
-		winner.NextLevelXP = winner.Level * winner.Level * 10
-		winner.RecalculateStats()
-		fmt.Printf("%s grew to Level %d!\n", winner.Nickname, winner.Level)
+	// A big XP gain can span several levels, so check evolution at each one
+	for winner.CanLevelUp() {
+		winner.LevelUp(curve)
+		b.emit(Event{Kind: EventLevelUp, Target: b.Name(winner), Amount: winner.Level})
 
 		// 2. GENERIC EVOLUTION CHECK
 		// Instead of "if charmander...", we ask the generic helper:
-		handleLevelUpEvolution(winner, client)
+		b.handleLevelUpEvolution(winner, ctx)
 	}
 }
 
-func handleLevelUpEvolution(p *BattlePokemon, client pokeapi.Client) {
+func (b *Battle) handleLevelUpEvolution(p *BattlePokemon, ctx EvolutionContext) {
+	client := *b.client
+
 	// A. Get Species to find the Chain URL
 	species, err := client.GetPokemonSpecies(p.Base.Name)
 	if err != nil {
@@ -307,65 +1275,122 @@ func handleLevelUpEvolution(p *BattlePokemon, client pokeapi.Client) {
 		return
 	}
 
-	// C. Check if we should evolve
-	// (Reuse the 'findNextEvolution' helper we wrote for the command)
-	nextStageName, minLevel, itemReq := FindNextEvolution(chainData.Chain, p.Base.Name)
+	// C. Auto-Evolve into the first branch that:
+	// 1. Is triggered by leveling up
+	// 2. Has all of its conditions met
+	// 3. Needs NO item (Items usually require manual trigger)
+	for _, option := range FindEvolutions(chainData.Chain, p.Base.Name) {
+		if !option.AutoEvolves() || len(option.UnmetConditions(p, ctx)) > 0 {
+			continue
+		}
 
-	// D. Auto-Evolve Conditions:
-	// 1. Next stage exists
-	// 2. We meet the level requirement
-	// 3. NO item is required (Items usually require manual trigger)
-	if nextStageName != "" && p.Level >= minLevel && itemReq == "" {
-		fmt.Printf("\n...Wait! %s is evolving!\n", p.Nickname)
+		b.emit(Event{Kind: EventEvolving, Target: b.Name(p)})
 
 		// Fetch new form
-		newBase, err := client.GetPokemon(nextStageName)
+		newBase, err := client.GetPokemon(option.NextStage)
 		if err != nil {
-			fmt.Println("Evolution failed due to connection error.")
+			b.emit(Event{Kind: EventEvolveFailed, Target: b.Name(p)})
 			return
 		}
 
 		// Execute Evolution
-		p.Evolve(newBase, client)
-		fmt.Printf("Congratulations! Your %s evolved into %s!\n", p.Base.Name, newBase.Name)
+		oldName := p.Base.Name
+		p.EvolveVia(option, newBase, ctx, client, b.rng)
+		b.emit(Event{Kind: EventEvolved, Target: oldName, Species: newBase.Name})
+		return
 	}
 }
 
-// Helpers for menus (Switching, Fight) excluded for brevity but follow similar pattern
-func handleFightMenu(scanner *bufio.Scanner, active *BattlePokemon, enemy *BattlePokemon) bool {
-	for i, m := range active.Moves {
-		fmt.Printf("%d. %s (%s) [%d/%d PP]\n", i+1, m.Name, m.Type, m.CurrentPP, m.MaxPP)
+// Name is how a pokemon is referred to in events
+func (b *Battle) Name(p *BattlePokemon) string {
+	if b.side(p) == SidePlayer {
+		return p.Nickname
 	}
-	scanner.Scan()
-	idx, _ := strconv.Atoi(scanner.Text())
-	if idx > 0 && idx <= len(active.Moves) {
-		performMove(active, enemy, &active.Moves[idx-1])
-		return true
+	if b.Trainer != nil {
+		return "Foe " + p.Nickname
 	}
-	return false
+	return "Wild " + p.Nickname
 }
 
-func forceSwitch(_ *bufio.Scanner, party []*BattlePokemon, active **BattlePokemon) bool {
-	// Loop through party to find replacement
-	for _, p := range party {
-		if p.Stats.HP > 0 {
-			*active = p
-			fmt.Printf("Go! %s!\n", p.Nickname)
-			return true
+// side is which side of the field a pokemon fights on
+func (b *Battle) side(p *BattlePokemon) string {
+	for _, o := range b.Opponents {
+		if p == o {
+			return SideOpponent
 		}
 	}
-	return false
+	return SidePlayer
 }
 
-func handleSwitchMenu(scanner *bufio.Scanner, party []*BattlePokemon) *BattlePokemon {
-	fmt.Println("Select Pokemon:")
-	for i, p := range party {
-		fmt.Printf("%d. %s (%d/%d HP)\n", i+1, p.Nickname, p.Stats.HP, p.Stats.MaxHP)
+func (b *Battle) emit(events ...Event) {
+	b.events = append(b.events, events...)
+	b.log = append(b.log, events...)
+}
+
+// emitFor adds events from helpers that only know the pokemon's nickname
+func (b *Battle) emitFor(p *BattlePokemon, events ...Event) {
+	for _, e := range events {
+		e.Target = b.Name(p)
+		b.emit(e)
 	}
-	scanner.Scan()
-	idx, _ := strconv.Atoi(scanner.Text())
-	if idx > 0 && idx <= len(party) && party[idx-1].Stats.HP > 0 {
-		return party[idx-1]
+}
+
+func (b *Battle) flush() []Event {
+	events := b.events
+	b.events = nil
+	return events
+}
+
+func isHealthy(p *BattlePokemon) bool {
+	return p.Status != StatusFainted && p.Stats.HP > 0
+}
+
+func addParticipant(participants []*BattlePokemon, p *BattlePokemon) []*BattlePokemon {
+	for _, existing := range participants {
+		if existing == p {
+			return participants
+		}
 	}
-	return nil
+	return append(participants, p)
+}
+
+// ShareExperience splits the XP for defeating loser between the conscious
+// participants. With the Exp. Share on, benched members get half a share each.
+func ShareExperience(party, participants []*BattlePokemon, loser *BattlePokemon, expShareOn bool) map[*BattlePokemon]int {
+	var fighters, benched []*BattlePokemon
+	for _, p := range party {
+		if p.Status == StatusFainted || p.Stats.HP <= 0 {
+			continue
+		}
+		isParticipant := false
+		for _, q := range participants {
+			if p == q {
+				isParticipant = true
+				break
+			}
+		}
+		if isParticipant {
+			fighters = append(fighters, p)
+		} else if expShareOn {
+			benched = append(benched, p)
+		}
+	}
+
+	shares := make(map[*BattlePokemon]int)
+	if len(fighters) == 0 {
+		return shares
+	}
+
+	totalXP := (loser.Base.BaseExperience * loser.Level) / 7
+	share := totalXP / len(fighters)
+	if share < 1 {
+		share = 1
+	}
+	for _, p := range fighters {
+		shares[p] = share
+	}
+	for _, p := range benched {
+		shares[p] = max(share/2, 1)
+	}
+	return shares
 }
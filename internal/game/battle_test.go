@@ -0,0 +1,477 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+func TestShareExperience(t *testing.T) {
+	newMon := func(name string, hp int) *BattlePokemon {
+		return &BattlePokemon{Nickname: name, Stats: Stats{HP: hp, MaxHP: 20}}
+	}
+	a, b, c, fainted := newMon("a", 20), newMon("b", 20), newMon("c", 20), newMon("d", 0)
+	fainted.Status = StatusFainted
+	party := []*BattlePokemon{a, b, c, fainted}
+
+	loser := &BattlePokemon{Base: pokeapi.Pokemon{BaseExperience: 140}, Level: 5} // 100 XP total
+
+	cases := []struct {
+		name         string
+		participants []*BattlePokemon
+		expShareOn   bool
+		expected     map[*BattlePokemon]int
+	}{
+		{
+			name:         "single participant",
+			participants: []*BattlePokemon{a},
+			expected:     map[*BattlePokemon]int{a: 100},
+		},
+		{
+			name:         "split between participants",
+			participants: []*BattlePokemon{a, b},
+			expected:     map[*BattlePokemon]int{a: 50, b: 50},
+		},
+		{
+			name:         "fainted participant gets nothing",
+			participants: []*BattlePokemon{fainted, a},
+			expected:     map[*BattlePokemon]int{a: 100},
+		},
+		{
+			name:         "exp share gives benched half",
+			participants: []*BattlePokemon{a},
+			expShareOn:   true,
+			expected:     map[*BattlePokemon]int{a: 100, b: 50, c: 50},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := ShareExperience(party, tc.participants, loser, tc.expShareOn)
+			if len(actual) != len(tc.expected) {
+				t.Errorf("expected %d shares, got %d", len(tc.expected), len(actual))
+				return
+			}
+			for p, xp := range tc.expected {
+				if actual[p] != xp {
+					t.Errorf("%s: expected %d XP, got %d", p.Nickname, xp, actual[p])
+				}
+			}
+		})
+	}
+}
+
+func newTestBattle(t *testing.T, party []*BattlePokemon, wild *BattlePokemon, inv *PlayerInventory) *Battle {
+	t.Helper()
+	b, events, err := NewBattle(BattleConfig{
+		Party:     party,
+		Wild:      wild,
+		Inventory: inv,
+		RNG:       NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != EventBattleStart {
+		t.Fatalf("expected a battle start event, got %v", events)
+	}
+	return b
+}
+
+func TestBattleStepWins(t *testing.T) {
+	strong := newTestMon(t, "machamp", 50, "fighting")
+	strong.Moves = []Move{{Name: "cross-chop", Type: "fighting", Power: 100, Accuracy: 100, CurrentPP: 5, MaxPP: 5}}
+	wild := newTestMon(t, "rattata", 3, "normal")
+	wild.Moves = []Move{{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35}}
+
+	inv := &PlayerInventory{}
+	b := newTestBattle(t, []*BattlePokemon{strong}, wild, inv)
+	for turns := 0; !b.Over(); turns++ {
+		if turns > 10 {
+			t.Fatalf("battle didn't finish")
+		}
+		if _, err := b.Step(Action{Kind: ActionMove}); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+
+	if b.Outcome() != OutcomeWon {
+		t.Errorf("expected a win, got outcome %v", b.Outcome())
+	}
+	if inv.Money == 0 {
+		t.Errorf("expected prize money for winning")
+	}
+	if _, err := b.Step(Action{Kind: ActionRun}); err == nil {
+		t.Errorf("expected an error stepping a finished battle")
+	}
+}
+
+func TestBattleRejectsInvalidActions(t *testing.T) {
+	active := newTestMon(t, "pikachu", 10, "electric")
+	active.Moves = []Move{{Name: "thunder-shock", Type: "electric", Power: 40, Accuracy: 100, CurrentPP: 30, MaxPP: 30}}
+	benched := newTestMon(t, "squirtle", 10, "water")
+	fainted := newTestMon(t, "bulbasaur", 10, "grass")
+	fainted.Stats.HP = 0
+	fainted.Status = StatusFainted
+	wild := newTestMon(t, "pidgey", 5, "normal", "flying")
+
+	cases := []Action{
+		{Kind: ActionMove, Move: 3},
+		{Kind: ActionItem, Item: "potion", Target: 0},
+		{Kind: ActionItem, Item: "pokeball"},
+		{Kind: ActionItem, Item: "revive", Target: 1},
+		{Kind: ActionItem, Item: "rare-candy", Target: 0},
+		{Kind: ActionSwitch, Target: 0},
+		{Kind: ActionSwitch, Target: 2},
+		{Kind: ActionSwitch, Target: 7},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			inv := &PlayerInventory{Revives: 1}
+			b := newTestBattle(t, []*BattlePokemon{active, benched, fainted}, wild, inv)
+			if _, err := b.Step(c); err == nil {
+				t.Errorf("expected an error for %+v", c)
+			}
+			if b.Turn != 1 {
+				t.Errorf("an invalid action used up the turn")
+			}
+		})
+	}
+}
+
+func TestBattleReplayRecordsEveryEvent(t *testing.T) {
+	strong := newTestMon(t, "machamp", 50, "fighting")
+	strong.Moves = []Move{{Name: "cross-chop", Type: "fighting", Power: 100, Accuracy: 100, CurrentPP: 5, MaxPP: 5}}
+	wild := newTestMon(t, "rattata", 3, "normal")
+	wild.Moves = []Move{{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35}}
+
+	b, events, err := NewBattle(BattleConfig{Party: []*BattlePokemon{strong}, Wild: wild, RNG: NewRNG(1)})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+	for !b.Over() {
+		step, err := b.Step(Action{Kind: ActionMove})
+		if err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+		events = append(events, step...)
+	}
+
+	replay := b.Replay()
+	if !reflect.DeepEqual(replay.Events, events) {
+		t.Errorf("replay events differ from the ones Step returned")
+	}
+	if replay.Player != "machamp" || replay.Opponent != "rattata" || replay.Outcome != "won" || replay.Turns != 1 {
+		t.Errorf("unexpected replay header: %+v", replay)
+	}
+
+	data, err := json.Marshal(replay)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var loaded Replay
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Events, replay.Events) {
+		t.Errorf("events didn't survive a save and load")
+	}
+}
+
+func TestBattleIsReproducibleFromSeed(t *testing.T) {
+	play := func(seed int64) []Event {
+		mon := newTestMon(t, "pikachu", 8, "electric")
+		mon.Moves = []Move{{Name: "thunder-shock", Type: "electric", Power: 40, Accuracy: 70, CurrentPP: 30, MaxPP: 30, StatusEffect: StatusParalysis}}
+		wild := newTestMon(t, "pidgey", 8, "normal", "flying")
+		wild.Moves = []Move{{Name: "gust", Type: "flying", Power: 40, Accuracy: 70, CurrentPP: 35, MaxPP: 35}}
+
+		b, _, err := NewBattle(BattleConfig{Party: []*BattlePokemon{mon}, Wild: wild, RNG: NewRNG(seed)})
+		if err != nil {
+			t.Fatalf("NewBattle: %v", err)
+		}
+		for turns := 0; !b.Over() && turns < 50; turns++ {
+			if _, err := b.Step(Action{Kind: ActionMove}); err != nil {
+				t.Fatalf("Step: %v", err)
+			}
+		}
+		return b.Log()
+	}
+
+	if !reflect.DeepEqual(play(42), play(42)) {
+		t.Errorf("the same seed played out differently")
+	}
+	if reflect.DeepEqual(play(1), play(2)) {
+		t.Errorf("different seeds played out identically")
+	}
+}
+
+func TestTrainerBattle(t *testing.T) {
+	strong := newTestMon(t, "machamp", 50, "fighting")
+	strong.Moves = []Move{{Name: "cross-chop", Type: "fighting", Power: 100, Accuracy: 100, CurrentPP: 20, MaxPP: 20}}
+	first := newTestMon(t, "rattata", 3, "normal")
+	first.Moves = []Move{{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35}}
+	second := newTestMon(t, "pidgey", 4, "normal", "flying")
+	second.Moves = []Move{{Name: "gust", Type: "flying", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35}}
+
+	trainer := &Trainer{ID: "joey", Name: "Joey", Class: "youngster", Team: []TrainerPokemon{{Species: "rattata", Level: 3}, {Species: "pidgey", Level: 4}}}
+	inv := &PlayerInventory{Pokeballs: 5}
+	b, events, err := NewBattle(BattleConfig{
+		Party:       []*BattlePokemon{strong},
+		Trainer:     trainer,
+		TrainerTeam: []*BattlePokemon{first, second},
+		Inventory:   inv,
+		RNG:         NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+	if events[0].Kind != EventChallenge || b.Foe() != first {
+		t.Fatalf("expected joey to challenge with rattata first, got %v", events)
+	}
+
+	if _, err := b.Step(Action{Kind: ActionItem, Item: "pokeball"}); err == nil {
+		t.Errorf("expected catching a trainer's pokemon to fail")
+	}
+	if _, err := b.Step(Action{Kind: ActionRun}); err == nil {
+		t.Errorf("expected running from a trainer to fail")
+	}
+	if inv.Pokeballs != 5 {
+		t.Errorf("a blocked ball was used up")
+	}
+
+	events, err = b.Step(Action{Kind: ActionMove})
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if b.Over() || b.Foe() != second || b.OpponentsLeft() != 1 {
+		t.Fatalf("expected joey to send out pidgey after rattata fainted, got %v", events)
+	}
+
+	for turns := 0; !b.Over(); turns++ {
+		if turns > 10 {
+			t.Fatalf("battle didn't finish")
+		}
+		if _, err := b.Step(Action{Kind: ActionMove}); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+	if b.Outcome() != OutcomeWon {
+		t.Fatalf("expected to beat joey, got %v", b.Outcome())
+	}
+	if inv.Money != trainer.PrizeMoney() {
+		t.Errorf("expected the ₽%d prize, got ₽%d", trainer.PrizeMoney(), inv.Money)
+	}
+	if replay := b.Replay(); replay.Opponent != "Youngster Joey" {
+		t.Errorf("expected the replay to name the trainer, got %q", replay.Opponent)
+	}
+}
+
+func TestDisobedience(t *testing.T) {
+	play := func(obedience int) int {
+		mon := newTestMon(t, "charizard", 60, "fire")
+		mon.Moves = []Move{{Name: "ember", Type: "fire", Power: 0, Accuracy: 100, CurrentPP: 99, MaxPP: 99}}
+		wild := newTestMon(t, "magikarp", 5, "water")
+		wild.Moves = []Move{{Name: "splash", Type: "normal", Power: 0, Accuracy: 100, CurrentPP: 99, MaxPP: 99}}
+		b, _, err := NewBattle(BattleConfig{Party: []*BattlePokemon{mon}, Wild: wild, RNG: NewRNG(3), ObedienceLevel: obedience})
+		if err != nil {
+			t.Fatalf("NewBattle: %v", err)
+		}
+
+		disobeyed := 0
+		for i := 0; i < 50 && !b.Over(); i++ {
+			events, err := b.Step(Action{Kind: ActionMove})
+			if err != nil {
+				t.Fatalf("Step: %v", err)
+			}
+			for _, e := range events {
+				if e.Kind == EventDisobey {
+					disobeyed++
+				}
+			}
+		}
+		return disobeyed
+	}
+
+	if n := play(0); n != 0 {
+		t.Errorf("expected no disobedience without a level cap, got %d", n)
+	}
+	if n := play(MaxLevel); n != 0 {
+		t.Errorf("expected no disobedience under the level cap, got %d", n)
+	}
+	if n := play(BaseObedienceLevel); n == 0 {
+		t.Errorf("expected a level 60 to disobey a trainer with no badges")
+	}
+}
+
+func TestDoubleBattle(t *testing.T) {
+	newDouble := func(t *testing.T) (*Battle, []*BattlePokemon, []*BattlePokemon) {
+		t.Helper()
+		fast := newTestMon(t, "machamp", 40, "fighting")
+		fast.Moves = []Move{
+			{Name: "rock-slide", Type: "rock", Power: 75, Accuracy: 100, CurrentPP: 10, MaxPP: 10, Target: TargetAllOpponents},
+			{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35, Target: TargetSelected},
+		}
+		slow := newTestMon(t, "rattata", 5, "normal")
+		slow.Moves = []Move{{Name: "quick-attack", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 30, MaxPP: 30, Priority: 1, Target: TargetSelected}}
+
+		var foes []*BattlePokemon
+		for _, name := range []string{"oddish", "poliwag"} {
+			foe := newTestMon(t, name, 30, "water")
+			foe.Moves = []Move{{Name: "harden", Type: "normal", Accuracy: 100, CurrentPP: 30, MaxPP: 30, Target: "user"}}
+			foes = append(foes, foe)
+		}
+
+		trainer := &Trainer{ID: "twins", Name: "Amy & May", Class: "twins", Double: true}
+		b, events, err := NewBattle(BattleConfig{
+			Party:       []*BattlePokemon{fast, slow},
+			Trainer:     trainer,
+			TrainerTeam: foes,
+			Double:      true,
+			OpponentAI:  RandomAI{},
+			RNG:         NewRNG(1),
+		})
+		if err != nil {
+			t.Fatalf("NewBattle: %v", err)
+		}
+		if len(b.Actives()) != 2 || len(b.Foes()) != 2 {
+			t.Fatalf("expected two pokemon a side, got %v", events)
+		}
+		return b, []*BattlePokemon{fast, slow}, foes
+	}
+
+	t.Run("Test case 0", func(t *testing.T) {
+		b, party, foes := newDouble(t)
+		if _, err := b.Step(Action{Kind: ActionMove}); err == nil {
+			t.Errorf("expected an error when only one pokemon was given an action")
+		}
+
+		events, err := b.Step(Action{Kind: ActionMove, Move: 0}, Action{Kind: ActionMove, Move: 0, FoeTarget: 1})
+		if err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+
+		var order []string
+		var spreadHits []Event
+		for _, e := range events {
+			if e.Kind == EventMove {
+				order = append(order, e.Move)
+			}
+			if e.Kind == EventDamage && order[len(order)-1] == "rock-slide" {
+				spreadHits = append(spreadHits, e)
+			}
+		}
+		want := "[quick-attack rock-slide harden harden]"
+		if fmt.Sprint(order) != want {
+			t.Fatalf("expected priority then speed order %s, got %v", want, order)
+		}
+
+		if len(spreadHits) != 2 || spreadHits[0].Target == spreadHits[1].Target {
+			t.Fatalf("expected rock-slide to hit both foes, got %v", spreadHits)
+		}
+		single := CalculateDamage(party[0], foes[0], &party[0].Moves[0], true, MaxDamageRoll)
+		for _, hit := range spreadHits {
+			if hit.Amount > int(float64(single)*SpreadMultiplier) {
+				t.Errorf("expected spread damage to be reduced, got %d of a possible %d", hit.Amount, single)
+			}
+		}
+	})
+
+	t.Run("Test case 1", func(t *testing.T) {
+		b, _, foes := newDouble(t)
+		foes[0].Stats.HP = 1
+
+		// Quick Attack knocks out the first foe, so the tackle aimed at it hits its partner
+		events, err := b.Step(Action{Kind: ActionMove, Move: 1}, Action{Kind: ActionMove})
+		if err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+		for _, e := range events {
+			if e.Kind == EventMove && e.Move == "tackle" && e.Target != b.Name(foes[1]) {
+				t.Errorf("expected tackle to be redirected to %s, got %s", b.Name(foes[1]), e.Target)
+			}
+		}
+		if foes[1].Stats.HP == foes[1].Stats.MaxHP {
+			t.Errorf("expected the partner to take the redirected hit")
+		}
+		if len(b.Foes()) != 1 || b.Foe() != foes[1] {
+			t.Errorf("expected the last foe to stand alone, got %v", b.Foes())
+		}
+	})
+}
+
+func TestChooseReplacement(t *testing.T) {
+	weak := newTestMon(t, "caterpie", 5, "bug")
+	weak.Moves = []Move{{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35}}
+	second := newTestMon(t, "pidgey", 5, "normal", "flying")
+	third := newTestMon(t, "squirtle", 5, "water")
+	foe := newTestMon(t, "machamp", 50, "fighting")
+	foe.Moves = []Move{{Name: "cross-chop", Type: "fighting", Power: 100, Accuracy: 100, CurrentPP: 20, MaxPP: 20}}
+
+	b, _, err := NewBattle(BattleConfig{
+		Party:              []*BattlePokemon{weak, second, third},
+		Wild:               foe,
+		RNG:                NewRNG(1),
+		ChooseReplacements: true,
+	})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+	if _, err := b.Step(Action{Kind: ActionMove}); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if fmt.Sprint(b.NeedsReplacement()) != "[0]" || b.DefaultReplacement() != 1 {
+		t.Fatalf("expected to be asked for a replacement, got %v", b.NeedsReplacement())
+	}
+	if _, err := b.Step(Action{Kind: ActionMove}); err == nil {
+		t.Errorf("expected a turn to wait for the replacement")
+	}
+
+	cases := []struct {
+		pos, idx int
+		ok       bool
+	}{
+		{1, 2, false}, // No such field position in a single battle
+		{0, 0, false}, // Fainted itself
+		{0, 5, false},
+		{0, 2, true},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			events, err := b.Replace(c.pos, c.idx)
+			if (err == nil) != c.ok {
+				t.Fatalf("expected ok=%v, got %v", c.ok, err)
+			}
+			if c.ok && (b.Active() != third || len(b.NeedsReplacement()) != 0 || events[0].Kind != EventSwitch) {
+				t.Errorf("expected squirtle to be sent out, got %v", events)
+			}
+		})
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	wild := newTestBattle(t, []*BattlePokemon{newTestMon(t, "slowpoke", 5, "water")}, newTestMon(t, "pidgey", 5, "normal"), nil)
+	if events := wild.Forfeit(); !hasEvent(events, EventRun) || wild.Outcome() != OutcomeFled {
+		t.Errorf("expected to flee a wild battle, got %v and %v", events, wild.Outcome())
+	}
+	if events := wild.Forfeit(); events != nil {
+		t.Errorf("expected nothing to happen once the battle is over, got %v", events)
+	}
+
+	trainer := &Trainer{ID: "joey", Name: "Joey", Class: "youngster"}
+	b, _, err := NewBattle(BattleConfig{
+		Party:       []*BattlePokemon{newTestMon(t, "slowpoke", 5, "water")},
+		Trainer:     trainer,
+		TrainerTeam: []*BattlePokemon{newTestMon(t, "rattata", 3, "normal")},
+		RNG:         NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+	if events := b.Forfeit(); !hasEvent(events, EventForfeit) || b.Outcome() != OutcomeLost {
+		t.Errorf("expected to lose to the trainer, got %v and %v", events, b.Outcome())
+	}
+}
@@ -0,0 +1,54 @@
+package game
+
+import "math"
+
+// DefaultCaptureRate is used when the species' capture rate couldn't be fetched
+const DefaultCaptureRate = 45
+
+// CatchResult says how many times the ball shook and whether it held
+type CatchResult struct {
+	Shakes int
+	Caught bool
+}
+
+// statusCatchBonus makes sleeping or frozen pokemon the easiest to catch
+func statusCatchBonus(status StatusID) float64 {
+	switch status {
+	case StatusSleep, StatusFreeze:
+		return 2.5
+	case StatusParalysis, StatusPoison, StatusBurn:
+		return 1.5
+	default:
+		return 1.0
+	}
+}
+
+// CatchValue is the mainline modified catch rate "a" (0-255)
+func CatchValue(wild *BattlePokemon, ballBonus float64) float64 {
+	rate := wild.CaptureRate
+	if rate <= 0 {
+		rate = DefaultCaptureRate
+	}
+	maxHP := float64(max(wild.Stats.MaxHP, 1))
+	hp := float64(wild.Stats.HP)
+
+	a := ((3*maxHP - 2*hp) * float64(rate) * ballBonus) / (3 * maxHP)
+	return a * statusCatchBonus(wild.Status)
+}
+
+// AttemptCatch runs the four shake checks. A Master Ball always succeeds.
+func AttemptCatch(wild *BattlePokemon, ballBonus float64, masterBall bool, rng *RNG) CatchResult {
+	a := CatchValue(wild, ballBonus)
+	if masterBall || a >= 255 {
+		return CatchResult{Shakes: 3, Caught: true}
+	}
+
+	// Each shake passes with probability b/65536
+	b := 65536 / math.Pow(255/a, 0.1875)
+	for shakes := 0; shakes < 4; shakes++ {
+		if float64(rng.Intn(65536)) >= b {
+			return CatchResult{Shakes: shakes}
+		}
+	}
+	return CatchResult{Shakes: 3, Caught: true}
+}
@@ -0,0 +1,43 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCatchValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		rate     int
+		hp       int
+		status   StatusID
+		ball     float64
+		expected float64
+	}{
+		{name: "full HP caterpie", rate: 255, hp: 30, ball: 1.0, expected: 85},
+		{name: "full HP legendary", rate: 3, hp: 30, ball: 1.0, expected: 1},
+		{name: "1 HP caterpie in an ultraball", rate: 255, hp: 1, ball: 2.0, expected: 498.6667},
+		{name: "poisoned gets 1.5x", rate: 45, hp: 30, status: StatusPoison, ball: 1.0, expected: 22.5},
+		{name: "asleep gets 2.5x", rate: 45, hp: 30, status: StatusSleep, ball: 1.0, expected: 37.5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			wild := &BattlePokemon{CaptureRate: c.rate, Status: c.status, Stats: Stats{HP: c.hp, MaxHP: 30}}
+			actual := CatchValue(wild, c.ball)
+			if math.Abs(actual-c.expected) > 0.001 {
+				t.Errorf("expected %.4f, got %.4f", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMasterBallAlwaysCatches(t *testing.T) {
+	wild := &BattlePokemon{CaptureRate: 3, Stats: Stats{HP: 300, MaxHP: 300}}
+	rng := NewRNG(1)
+	for i := 0; i < 100; i++ {
+		if !AttemptCatch(wild, 1.0, true, rng).Caught {
+			t.Fatal("expected the Master Ball to never fail")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package game
+
+// CritChance is the 1 in N chance of a critical hit
+const CritChance = 16
+
+// CritMultiplier is how much harder a critical hit lands
+const CritMultiplier = 1.5
+
+// STABMultiplier boosts moves that share a type with their user
+const STABMultiplier = 1.5
+
+// Every hit is scaled by a random roll between these percentages
+const (
+	MinDamageRoll = 85
+	MaxDamageRoll = 100
+)
+
+// baseDamage is the damage formula before type matchups:
+// ((2 * Level / 5 + 2) * Power * A / D) / 50 + 2
+func baseDamage(attacker, defender *BattlePokemon, move *Move) float64 {
+	power := movePower(attacker, move)
+	if power <= 0 {
+		return 0
+	}
+	atk := effectiveAttack(attacker)
+	def := float64(max(defender.Stats.Defense, 1))
+	damage := (((2.0*float64(attacker.Level)/5.0 + 2.0) * float64(power) * atk / def) / 50.0) + 2.0
+	return damage * heldItemDamageMultiplier(attacker, move)
+}
+
+// stab is the same-type attack bonus for a move
+func stab(attacker *BattlePokemon, move *Move) float64 {
+	if attacker.HasType(move.Type) {
+		return STABMultiplier
+	}
+	return 1.0
+}
+
+// CalculateDamage is the damage a hit deals, with type effectiveness, STAB, crits
+// and the damage roll (MinDamageRoll to MaxDamageRoll). It returns 0 when the defender
+// is immune or the move doesn't deal damage, like a status move.
+func CalculateDamage(attacker, defender *BattlePokemon, move *Move, crit bool, roll int) int {
+	effectiveness := GetTypeEffectiveness(move.Type, defender.TypeNames())
+	if effectiveness == 0 {
+		return 0
+	}
+
+	damage := baseDamage(attacker, defender, move)
+	if damage == 0 {
+		return 0
+	}
+	if crit {
+		damage *= CritMultiplier
+	}
+	damage = damage * float64(roll) / 100.0 * stab(attacker, move) * effectiveness
+	return max(int(damage), 1)
+}
+
+// rollDamage picks a random damage roll
+func rollDamage(rng *RNG) int {
+	return MinDamageRoll + rng.Intn(MaxDamageRoll-MinDamageRoll+1)
+}
+
+// DamageRange is every outcome a move can have against a defender
+type DamageRange struct {
+	Min, Max         int // Normal hits at the lowest and highest roll
+	CritMin, CritMax int
+	Effectiveness    float64
+}
+
+// CalculateDamageRange runs CalculateDamage at the extreme rolls, with and without a crit
+func CalculateDamageRange(attacker, defender *BattlePokemon, move *Move) DamageRange {
+	return DamageRange{
+		Min:           CalculateDamage(attacker, defender, move, false, MinDamageRoll),
+		Max:           CalculateDamage(attacker, defender, move, false, MaxDamageRoll),
+		CritMin:       CalculateDamage(attacker, defender, move, true, MinDamageRoll),
+		CritMax:       CalculateDamage(attacker, defender, move, true, MaxDamageRoll),
+		Effectiveness: GetTypeEffectiveness(move.Type, defender.TypeNames()),
+	}
+}
+
+// HitsToKO is how many non-critical hits it takes to knock out hp, at best and at worst.
+// Both are 0 when the move can't do damage.
+func (r DamageRange) HitsToKO(hp int) (best, worst int) {
+	if r.Max == 0 {
+		return 0, 0
+	}
+	return ceilDiv(hp, r.Max), ceilDiv(hp, r.Min)
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCalculateDamage(t *testing.T) {
+	attacker := newTestMon(t, "charmander", 20, "fire")
+	ember := &Move{Name: "ember", Type: "fire", Power: 40, Accuracy: 100}
+	tackle := &Move{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100}
+	growl := &Move{Name: "growl", Type: "normal", Accuracy: 100}
+	normalFoe := newTestMon(t, "rattata", 20, "normal")
+	grassFoe := newTestMon(t, "bulbasaur", 20, "grass")
+	waterFoe := newTestMon(t, "squirtle", 20, "water")
+
+	base := baseDamage(attacker, normalFoe, tackle)
+
+	cases := []struct {
+		defender *BattlePokemon
+		move     *Move
+		crit     bool
+		expected int
+	}{
+		{defender: normalFoe, move: tackle, expected: int(base)},
+		{defender: normalFoe, move: tackle, crit: true, expected: int(base * CritMultiplier)},
+		{defender: normalFoe, move: ember, expected: int(base * STABMultiplier)},
+		{defender: grassFoe, move: ember, expected: int(base * STABMultiplier * 2)},
+		{defender: waterFoe, move: ember, expected: int(base * STABMultiplier * 0.5)},
+		{defender: normalFoe, move: growl, expected: 0},
+		{defender: normalFoe, move: growl, crit: true, expected: 0},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			actual := CalculateDamage(attacker, c.defender, c.move, c.crit, MaxDamageRoll)
+			if actual != c.expected {
+				t.Errorf("%s vs %s (crit %v): expected %d, got %d", c.move.Name, c.defender.Nickname, c.crit, c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDamageRangeHitsToKO(t *testing.T) {
+	cases := []struct {
+		dmg       DamageRange
+		hp        int
+		bestHits  int
+		worstHits int
+	}{
+		{dmg: DamageRange{Min: 17, Max: 20}, hp: 40, bestHits: 2, worstHits: 3},
+		{dmg: DamageRange{Min: 40, Max: 48}, hp: 40, bestHits: 1, worstHits: 1},
+		{dmg: DamageRange{Min: 9, Max: 11}, hp: 100, bestHits: 10, worstHits: 12},
+		{dmg: DamageRange{}, hp: 40, bestHits: 0, worstHits: 0},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			best, worst := c.dmg.HitsToKO(c.hp)
+			if best != c.bestHits || worst != c.worstHits {
+				t.Errorf("expected %d-%d hits, got %d-%d", c.bestHits, c.worstHits, best, worst)
+			}
+		})
+	}
+}
+
+func TestCalculateDamageRange(t *testing.T) {
+	attacker := newTestMon(t, "charmander", 30, "fire")
+	defender := newTestMon(t, "bulbasaur", 30, "grass")
+	ember := &Move{Name: "ember", Type: "fire", Power: 40, Accuracy: 100}
+
+	r := CalculateDamageRange(attacker, defender, ember)
+	if r.Effectiveness != 2 {
+		t.Errorf("expected fire to be super effective on grass, got %v", r.Effectiveness)
+	}
+	if !(r.Min < r.Max && r.Max < r.CritMax && r.Min < r.CritMin) {
+		t.Errorf("range out of order: %+v", r)
+	}
+	if r.Max != CalculateDamage(attacker, defender, ember, false, MaxDamageRoll) {
+		t.Errorf("max doesn't match the battle damage at the top roll")
+	}
+}
+
+func TestStatusMoveDealsNoDamage(t *testing.T) {
+	attacker := newTestMon(t, "charmander", 30, "fire")
+	defender := newTestMon(t, "rattata", 30, "normal")
+	growl := &Move{Name: "growl", Type: "normal", Accuracy: 100, CurrentPP: 40, MaxPP: 40}
+
+	r := CalculateDamageRange(attacker, defender, growl)
+	if r.Max != 0 || r.CritMax != 0 {
+		t.Errorf("expected a status move to deal no damage, got %+v", r)
+	}
+	if best, worst := r.HitsToKO(defender.Stats.HP); best != 0 || worst != 0 {
+		t.Errorf("expected a status move to never KO, got %d-%d hits", best, worst)
+	}
+
+	attacker.Moves = []Move{*growl}
+	defender.Moves = []Move{*growl}
+	b := newTestBattle(t, []*BattlePokemon{attacker}, defender, &PlayerInventory{})
+	hp, foeHP := attacker.Stats.HP, defender.Stats.HP
+	events, err := b.Step(Action{Kind: ActionMove})
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if attacker.Stats.HP != hp || defender.Stats.HP != foeHP {
+		t.Errorf("expected growl to leave both at full HP, got %d/%d and %d/%d", attacker.Stats.HP, hp, defender.Stats.HP, foeHP)
+	}
+	if hasEvent(events, EventDamage) || hasEvent(events, EventEffectiveness) {
+		t.Errorf("expected no damage events for a status move, got %v", events)
+	}
+}
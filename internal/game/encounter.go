@@ -0,0 +1,184 @@
+package game
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+// DefaultVersion is the game version whose encounter tables are used when an area has them
+const DefaultVersion = "red"
+
+// DefaultEncounterRate is the chance per step of finding something in areas without a rate
+const DefaultEncounterRate = 25
+
+// MethodWalk is walking through tall grass, the default way to find wild pokemon
+const MethodWalk = "walk"
+
+// Encounter is one slot of an area's encounter table
+type Encounter struct {
+	Species    string
+	Method     string // "walk", "surf", "old-rod", ...
+	Chance     int    // Relative weight among slots with the same method
+	MinLevel   int
+	MaxLevel   int
+	Conditions []string // e.g. "time-night", all of which must hold
+}
+
+// EncounterTable is every wild pokemon an area holds in one game version
+type EncounterTable struct {
+	Area       string
+	Version    string
+	Encounters []Encounter
+	Rates      map[string]int // Chance out of 100 per step that a method finds anything
+}
+
+// NewEncounterTable reads an area's encounters for a version. Areas that
+// don't appear in that version fall back to the first version listed.
+func NewEncounterTable(area pokeapi.LocationAreaDetail, version string) EncounterTable {
+	if !hasVersion(area, version) {
+		version = ""
+		for _, pe := range area.PokemonEncounters {
+			if len(pe.VersionDetails) > 0 {
+				version = pe.VersionDetails[0].Version.Name
+				break
+			}
+		}
+	}
+
+	table := EncounterTable{Area: area.Name, Version: version, Rates: make(map[string]int)}
+	for _, pe := range area.PokemonEncounters {
+		for _, vd := range pe.VersionDetails {
+			if vd.Version.Name != version {
+				continue
+			}
+			for _, d := range vd.EncounterDetails {
+				e := Encounter{
+					Species:  pe.Pokemon.Name,
+					Method:   d.Method.Name,
+					Chance:   d.Chance,
+					MinLevel: max(d.MinLevel, 1),
+					MaxLevel: max(d.MaxLevel, d.MinLevel, 1),
+				}
+				for _, c := range d.ConditionValues {
+					e.Conditions = append(e.Conditions, c.Name)
+				}
+				table.Encounters = append(table.Encounters, e)
+			}
+		}
+	}
+	for _, mr := range area.EncounterMethodRates {
+		for _, vd := range mr.VersionDetails {
+			if vd.Version.Name == version {
+				table.Rates[mr.EncounterMethod.Name] = vd.Rate
+			}
+		}
+	}
+	return table
+}
+
+func hasVersion(area pokeapi.LocationAreaDetail, version string) bool {
+	for _, pe := range area.PokemonEncounters {
+		for _, vd := range pe.VersionDetails {
+			if vd.Version.Name == version {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Methods lists the ways to find pokemon here, e.g. ["old-rod", "walk"]
+func (t EncounterTable) Methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, e := range t.Encounters {
+		if !seen[e.Method] {
+			seen[e.Method] = true
+			methods = append(methods, e.Method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// At narrows the table to the slots that appear at a time of day. Slots
+// without a time condition appear all day long.
+func (t EncounterTable) At(timeOfDay string) EncounterTable {
+	narrowed := t
+	narrowed.Encounters = nil
+	for _, e := range t.Encounters {
+		if e.AppearsAt(timeOfDay) {
+			narrowed.Encounters = append(narrowed.Encounters, e)
+		}
+	}
+	return narrowed
+}
+
+// Times are the periods a slot is limited to, e.g. ["night"], or none if it appears all day
+func (e Encounter) Times() []string {
+	var times []string
+	for _, c := range e.Conditions {
+		if period, ok := strings.CutPrefix(c, "time-"); ok {
+			times = append(times, period)
+		}
+	}
+	return times
+}
+
+// AppearsAt checks a slot's time conditions. Encounter tables only know
+// morning, day and night, so dusk counts as day like it did in those games.
+func (e Encounter) AppearsAt(timeOfDay string) bool {
+	if timeOfDay == "dusk" {
+		timeOfDay = "day"
+	}
+	for _, period := range e.Times() {
+		if period != timeOfDay {
+			return false
+		}
+	}
+	return true
+}
+
+// Slots are the encounters for one method
+func (t EncounterTable) Slots(method string) []Encounter {
+	var slots []Encounter
+	for _, e := range t.Encounters {
+		if e.Method == method {
+			slots = append(slots, e)
+		}
+	}
+	return slots
+}
+
+// Roll picks a wild pokemon for a method, weighted by each slot's chance,
+// and a level in the slot's range. It's false when the method finds nothing here.
+func (t EncounterTable) Roll(method string, rng *RNG) (Encounter, int, bool) {
+	slots := t.Slots(method)
+	total := 0
+	for _, e := range slots {
+		total += e.Chance
+	}
+	if total <= 0 {
+		return Encounter{}, 0, false
+	}
+
+	roll := rng.Intn(total)
+	for _, e := range slots {
+		if roll < e.Chance {
+			return e, e.MinLevel + rng.Intn(e.MaxLevel-e.MinLevel+1), true
+		}
+		roll -= e.Chance
+	}
+	return Encounter{}, 0, false
+}
+
+// Step rolls whether walking one step with a method finds anything, using the area's encounter rate
+func (t EncounterTable) Step(method string, rng *RNG) bool {
+	rate, ok := t.Rates[method]
+	if !ok {
+		rate = DefaultEncounterRate
+	}
+	return rng.Intn(100) < rate
+}
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+// routeOne is a trimmed down PokeAPI location area
+const routeOne = `{
+	"name": "kanto-route-1-area",
+	"location": {"name": "kanto-route-1"},
+	"encounter_method_rates": [
+		{"encounter_method": {"name": "walk"}, "version_details": [{"rate": 25, "version": {"name": "red"}}, {"rate": 30, "version": {"name": "gold"}}]}
+	],
+	"pokemon_encounters": [
+		{"pokemon": {"name": "pidgey"}, "version_details": [
+			{"version": {"name": "red"}, "max_chance": 70, "encounter_details": [
+				{"min_level": 2, "max_level": 5, "chance": 70, "method": {"name": "walk"}, "condition_values": []}
+			]},
+			{"version": {"name": "gold"}, "max_chance": 50, "encounter_details": [
+				{"min_level": 2, "max_level": 4, "chance": 50, "method": {"name": "walk"}, "condition_values": [{"name": "time-morning"}]}
+			]}
+		]},
+		{"pokemon": {"name": "rattata"}, "version_details": [
+			{"version": {"name": "red"}, "max_chance": 30, "encounter_details": [
+				{"min_level": 3, "max_level": 3, "chance": 30, "method": {"name": "walk"}, "condition_values": []}
+			]}
+		]},
+		{"pokemon": {"name": "poliwag"}, "version_details": [
+			{"version": {"name": "gold"}, "max_chance": 100, "encounter_details": [
+				{"min_level": 10, "max_level": 15, "chance": 100, "method": {"name": "surf"}, "condition_values": []}
+			]}
+		]}
+	]
+}`
+
+func loadRouteOne(t *testing.T) pokeapi.LocationAreaDetail {
+	t.Helper()
+	var area pokeapi.LocationAreaDetail
+	if err := json.Unmarshal([]byte(routeOne), &area); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	return area
+}
+
+func TestNewEncounterTable(t *testing.T) {
+	area := loadRouteOne(t)
+	cases := []struct {
+		version     string
+		wantVersion string
+		methods     string
+		rate        int
+	}{
+		{"red", "red", "[walk]", 25},
+		{"gold", "gold", "[surf walk]", 30},
+		{"emerald", "red", "[walk]", 25}, // Not in this area, so the first version listed
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			table := NewEncounterTable(area, c.version)
+			if table.Version != c.wantVersion {
+				t.Errorf("expected version %s, got %s", c.wantVersion, table.Version)
+			}
+			if got := fmt.Sprint(table.Methods()); got != c.methods {
+				t.Errorf("expected methods %s, got %s", c.methods, got)
+			}
+			if table.Rates[MethodWalk] != c.rate {
+				t.Errorf("expected a walk rate of %d, got %d", c.rate, table.Rates[MethodWalk])
+			}
+		})
+	}
+
+	gold := NewEncounterTable(area, "gold")
+	if slots := gold.Slots(MethodWalk); len(slots) != 1 || fmt.Sprint(slots[0].Conditions) != "[time-morning]" {
+		t.Errorf("expected pidgey's time of day condition, got %+v", slots)
+	}
+}
+
+func TestEncounterRoll(t *testing.T) {
+	table := NewEncounterTable(loadRouteOne(t), "red")
+	rng := NewRNG(1)
+
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		e, level, ok := table.Roll(MethodWalk, rng)
+		if !ok {
+			t.Fatalf("expected walking to find something")
+		}
+		if level < e.MinLevel || level > e.MaxLevel {
+			t.Fatalf("%s at level %d is outside %d-%d", e.Species, level, e.MinLevel, e.MaxLevel)
+		}
+		counts[e.Species]++
+	}
+	if counts["pidgey"] < 600 || counts["pidgey"] > 800 || counts["pidgey"]+counts["rattata"] != 1000 {
+		t.Errorf("expected about 70%% pidgey and 30%% rattata, got %v", counts)
+	}
+
+	if _, _, ok := table.Roll("surf", rng); ok {
+		t.Errorf("expected nothing to be found surfing in red")
+	}
+}
+
+func TestEncounterTableAt(t *testing.T) {
+	gold := NewEncounterTable(loadRouteOne(t), "gold")
+	cases := []struct {
+		timeOfDay string
+		walk      int
+		surf      int
+	}{
+		{"morning", 1, 1},
+		{"day", 0, 1},
+		{"dusk", 0, 1},
+		{"night", 0, 1},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			table := gold.At(c.timeOfDay)
+			if got := len(table.Slots(MethodWalk)); got != c.walk {
+				t.Errorf("expected %d walking slots in the %s, got %d", c.walk, c.timeOfDay, got)
+			}
+			if got := len(table.Slots("surf")); got != c.surf {
+				t.Errorf("expected %d surfing slots in the %s, got %d", c.surf, c.timeOfDay, got)
+			}
+		})
+	}
+
+	if len(gold.Encounters) != 2 {
+		t.Errorf("expected At to leave the full table alone, got %+v", gold.Encounters)
+	}
+}
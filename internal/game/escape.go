@@ -0,0 +1,23 @@
+package game
+
+// EscapeCertain is the escape odds when running away can't fail
+const EscapeCertain = 256
+
+// EscapeOdds is the chance out of 256 of fleeing a wild battle, using the
+// mainline formula: the faster the runner and the more attempts it's had
+// this battle, the better. attempts counts this one.
+func EscapeOdds(speed, foeSpeed, attempts int) int {
+	if speed >= foeSpeed {
+		return EscapeCertain
+	}
+	divisor := (foeSpeed / 4) % 256
+	if divisor == 0 {
+		return EscapeCertain
+	}
+	return min(speed*32/divisor+30*attempts, EscapeCertain)
+}
+
+// alwaysEscapes covers the items, abilities and types that guarantee a getaway
+func alwaysEscapes(p *BattlePokemon) bool {
+	return p.HeldItem == "smoke-ball" || p.Ability == "run-away" || p.HasType("ghost")
+}
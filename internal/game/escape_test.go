@@ -0,0 +1,65 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEscapeOdds(t *testing.T) {
+	cases := []struct {
+		speed, foeSpeed, attempts int
+		odds                      int
+	}{
+		{50, 50, 1, EscapeCertain},
+		{60, 40, 1, EscapeCertain},
+		{20, 100, 1, 20*32/25 + 30},
+		{20, 100, 3, 20*32/25 + 90},
+		{10, 400, 1, 10*32/100 + 30},
+		{10, 400, 9, EscapeCertain},
+		{1, 3, 1, EscapeCertain}, // The foe's speed rounds down to nothing
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := EscapeOdds(c.speed, c.foeSpeed, c.attempts); got != c.odds {
+				t.Errorf("expected %d/256, got %d", c.odds, got)
+			}
+		})
+	}
+}
+
+func TestRunAway(t *testing.T) {
+	newRun := func(t *testing.T, runner *BattlePokemon) *Battle {
+		foe := newTestMon(t, "ninjask", 100, "bug")
+		foe.Moves = []Move{{Name: "harden", Type: "normal", Accuracy: 100, CurrentPP: 99, MaxPP: 99, Target: "user"}}
+		return newTestBattle(t, []*BattlePokemon{runner}, foe, nil)
+	}
+
+	slow := newTestMon(t, "slowpoke", 5, "water")
+	b := newRun(t, slow)
+	for i := 1; !b.Over(); i++ {
+		if i > 20 {
+			t.Fatalf("expected more attempts to make escape certain")
+		}
+		events, err := b.Step(Action{Kind: ActionRun})
+		if err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+		if b.EscapeAttempts() != i {
+			t.Errorf("expected %d attempts, got %d", i, b.EscapeAttempts())
+		}
+		if !b.Over() && !hasEvent(events, EventRunFailed) {
+			t.Errorf("expected a failed escape, got %v", events)
+		}
+	}
+	if b.Outcome() != OutcomeFled {
+		t.Errorf("expected to flee, got %v", b.Outcome())
+	}
+
+	smoky := newTestMon(t, "slowpoke", 5, "water")
+	smoky.HeldItem = "smoke-ball"
+	b = newRun(t, smoky)
+	if events, _ := b.Step(Action{Kind: ActionRun}); b.Outcome() != OutcomeFled || events[0].Item != "smoke-ball" {
+		t.Errorf("expected the smoke ball to guarantee escape, got %v", events)
+	}
+}
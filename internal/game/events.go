@@ -0,0 +1,72 @@
+package game
+
+// EventKind says what happened in a battle event
+type EventKind string
+
+const (
+	EventChallenge        EventKind = "challenge"          // Trainer Actor wants to battle
+	EventBattleStart      EventKind = "battle_start"       // Actor vs Target
+	EventDisobey          EventKind = "disobey"            // Actor ignored the player's order
+	EventMove             EventKind = "move"               // Side's Actor used Move on Target
+	EventNoTarget         EventKind = "no_target"          // Actor's move had nobody left to hit
+	EventFailed           EventKind = "failed"             // The move did nothing
+	EventMiss             EventKind = "miss"               // Actor's move missed Target
+	EventCrit             EventKind = "crit"               // Actor landed a critical hit
+	EventEffectiveness    EventKind = "effectiveness"      // The move hit Target with a type Multiplier
+	EventDamage           EventKind = "damage"             // Side's Target lost Amount HP
+	EventStatus           EventKind = "status"             // Target got Status
+	EventCantMove         EventKind = "cant_move"          // Actor's Status stopped it
+	EventStatusEnd        EventKind = "status_end"         // Target recovered from Status
+	EventHeal             EventKind = "heal"               // Target restored Amount HP using Item (given by Actor)
+	EventCure             EventKind = "cure"               // Target's Item cured its Status
+	EventRevive           EventKind = "revive"             // Target was revived
+	EventBallThrown       EventKind = "ball_thrown"        // The player threw Item
+	EventShake            EventKind = "shake"              // The ball shook
+	EventCaught           EventKind = "caught"             // Target was caught in Item
+	EventBreakFree        EventKind = "break_free"         // Target escaped after Amount shakes
+	EventFaint            EventKind = "faint"              // Target fainted
+	EventSwitch           EventKind = "switch"             // Side sent out Target (Actor is the trainer)
+	EventRun              EventKind = "run"                // The player got away, thanks to Actor's Item or Ability if set
+	EventRunFailed        EventKind = "run_failed"         // The player couldn't escape on attempt Amount
+	EventTrainerDefeated  EventKind = "trainer_defeated"   // The player beat trainer Actor
+	EventMoney            EventKind = "money"              // The player received Amount money
+	EventXP               EventKind = "xp"                 // Target gained Amount XP
+	EventLevelUp          EventKind = "level_up"           // Target grew to level Amount
+	EventEvolving         EventKind = "evolving"           // Target started evolving
+	EventEvolved          EventKind = "evolved"            // Target evolved into Species
+	EventEvolveFailed     EventKind = "evolve_failed"      // Target's evolution couldn't be fetched
+	EventWeatherStart     EventKind = "weather_start"      // Weather started, from Actor's Ability if set
+	EventWeather          EventKind = "weather"            // Weather carries on
+	EventWeatherEnd       EventKind = "weather_end"        // Weather stopped
+	EventWeatherDamage    EventKind = "weather_damage"     // Weather hurt Side's Target for Amount HP
+	EventSideCondition    EventKind = "side_condition"     // Move's hazard or screen went up on Side, Amount layers deep
+	EventSideConditionEnd EventKind = "side_condition_end" // Move's condition on Side wore off, or Target absorbed it
+	EventHazardDamage     EventKind = "hazard_damage"      // Hazards hurt Side's Target for Amount HP on entry
+	EventBlackout         EventKind = "blackout"           // The player has no pokemon left
+	EventForfeit          EventKind = "forfeit"            // The player gave up against trainer Actor
+)
+
+const (
+	SidePlayer   = "player"
+	SideOpponent = "opponent"
+)
+
+// Event is one thing that happened in a battle.
+// Only the fields relevant to the Kind are set.
+type Event struct {
+	Kind    EventKind `json:"kind"`
+	Side    string    `json:"side,omitempty"`
+	Actor   string    `json:"actor,omitempty"`
+	Target  string    `json:"target,omitempty"`
+	Move    string    `json:"move,omitempty"`
+	Item    string    `json:"item,omitempty"`
+	Species string    `json:"species,omitempty"`
+	Amount  int       `json:"amount,omitempty"`
+	HP      int       `json:"hp,omitempty"`
+	MaxHP   int       `json:"max_hp,omitempty"`
+	Status  StatusID  `json:"status,omitempty"`
+	Weather Weather   `json:"weather,omitempty"`
+	Ability string    `json:"ability,omitempty"`
+	// Multiplier is the type effectiveness for EventEffectiveness
+	Multiplier float64 `json:"multiplier,omitempty"`
+}
@@ -1,34 +1,254 @@
 package game
 
-import "github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+import (
+	"fmt"
+	"strings"
 
-func FindNextEvolution(chain pokeapi.ChainLink, currentName string) (nextName string, level int, item string) {
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+// FindEvolutions returns every branch the current species can evolve into.
+// A branch reachable in several ways (e.g. different games) is listed once per way.
+func FindEvolutions(chain pokeapi.ChainLink, currentName string) []EvolutionRequirement {
 	if chain.Species.Name == currentName {
-		if len(chain.EvolvesTo) > 0 {
-			nextLink := chain.EvolvesTo[0]
-
-			nextName = nextLink.Species.Name
-
-			if len(nextLink.EvolutionDetails) > 0 {
-				details := nextLink.EvolutionDetails[0]
-				if details.MinLevel != nil {
-					level = *details.MinLevel
-				}
-				if details.Item != nil {
-					item = details.Item.Name
-				}
+		var options []EvolutionRequirement
+		for _, next := range chain.EvolvesTo {
+			if len(next.EvolutionDetails) == 0 {
+				options = append(options, EvolutionRequirement{NextStage: next.Species.Name, Trigger: "level-up"})
+				continue
+			}
+			for _, details := range next.EvolutionDetails {
+				options = append(options, newEvolutionRequirement(next.Species.Name, details))
 			}
-			return
 		}
-		return "", 0, ""
+		return options
 	}
 
 	for _, child := range chain.EvolvesTo {
-		n, l, i := FindNextEvolution(child, currentName)
-		if n != "" {
-			return n, l, i
+		if options := FindEvolutions(child, currentName); len(options) > 0 {
+			return options
+		}
+	}
+
+	return nil
+}
+
+func newEvolutionRequirement(nextStage string, details pokeapi.EvolutionDetail) EvolutionRequirement {
+	req := EvolutionRequirement{
+		NextStage:     nextStage,
+		Trigger:       details.Trigger.Name,
+		TimeOfDay:     details.TimeOfDay,
+		PhysicalStats: details.RelativePhysicalStats,
+	}
+	if details.MinLevel != nil {
+		req.RequiredLevel = *details.MinLevel
+	}
+	if details.Item != nil {
+		req.RequiredStone = details.Item.Name
+	}
+	if details.MinHappiness != nil {
+		req.MinFriendship = *details.MinHappiness
+	}
+	// Affection was folded back into friendship in later games
+	if details.MinAffection != nil && *details.MinAffection > req.MinFriendship {
+		req.MinFriendship = *details.MinAffection
+	}
+	if details.HeldItem != nil {
+		req.HeldItem = details.HeldItem.Name
+	}
+	if details.KnownMove != nil {
+		req.KnownMove = details.KnownMove.Name
+	}
+	if details.KnownMoveType != nil {
+		req.KnownMoveType = details.KnownMoveType.Name
+	}
+	if details.Location != nil {
+		req.Location = details.Location.Name
+	}
+	if details.PartySpecies != nil {
+		req.PartySpecies = details.PartySpecies.Name
+	}
+	if details.PartyType != nil {
+		req.PartyType = details.PartyType.Name
+	}
+	if details.TradeSpecies != nil {
+		req.TradeSpecies = details.TradeSpecies.Name
+	}
+	if details.Gender != nil {
+		switch *details.Gender {
+		case 1:
+			req.Gender = "female"
+		case 2:
+			req.Gender = "male"
+		}
+	}
+
+	if details.MinBeauty != nil {
+		req.Unsupported = append(req.Unsupported, fmt.Sprintf("beauty %d+", *details.MinBeauty))
+	}
+	if details.NeedsOverworldRain {
+		req.Unsupported = append(req.Unsupported, "overworld rain")
+	}
+	if details.TurnUpsideDown {
+		req.Unsupported = append(req.Unsupported, "turning the console upside down")
+	}
+	return req
+}
+
+// UnmetConditions lists what is still missing for this evolution. Empty means it can happen now.
+func (r EvolutionRequirement) UnmetConditions(p *BattlePokemon, ctx EvolutionContext) []string {
+	var unmet []string
+
+	switch r.Trigger {
+	case "level-up", "use-item":
+	case "trade":
+		if !ctx.Trading {
+			unmet = append(unmet, "be traded")
+		}
+	case "":
+		unmet = append(unmet, "use an unknown evolution method")
+	default:
+		unmet = append(unmet, fmt.Sprintf("use the unsupported '%s' method", r.Trigger))
+	}
+
+	if r.RequiredLevel > 0 && p.Level < r.RequiredLevel {
+		unmet = append(unmet, fmt.Sprintf("reach level %d", r.RequiredLevel))
+	}
+	if r.RequiredStone != "" && (ctx.Inventory == nil || ctx.Inventory.EvolutionStones[r.RequiredStone] <= 0) {
+		unmet = append(unmet, fmt.Sprintf("have a %s", r.RequiredStone))
+	}
+	if r.MinFriendship > 0 && p.Friendship < r.MinFriendship {
+		unmet = append(unmet, fmt.Sprintf("reach friendship %d (currently %d)", r.MinFriendship, p.Friendship))
+	}
+	if !MatchesTimeOfDay(r.TimeOfDay, ctx.TimeOfDay) {
+		unmet = append(unmet, fmt.Sprintf("wait until %s", r.TimeOfDay))
+	}
+	if r.HeldItem != "" && p.HeldItem != r.HeldItem {
+		unmet = append(unmet, fmt.Sprintf("hold a %s", r.HeldItem))
+	}
+	if r.KnownMove != "" && !p.KnowsMove(r.KnownMove) {
+		unmet = append(unmet, fmt.Sprintf("know %s", r.KnownMove))
+	}
+	if r.KnownMoveType != "" && !p.KnowsMoveOfType(r.KnownMoveType) {
+		unmet = append(unmet, fmt.Sprintf("know a %s-type move", r.KnownMoveType))
+	}
+	if r.Location != "" && ctx.Location != r.Location {
+		unmet = append(unmet, fmt.Sprintf("be at %s", r.Location))
+	}
+	if r.Gender != "" && p.Gender != r.Gender {
+		unmet = append(unmet, fmt.Sprintf("be %s", r.Gender))
+	}
+	if r.PartySpecies != "" && !partyHas(ctx.Party, p, func(q *BattlePokemon) bool { return q.Base.Name == r.PartySpecies }) {
+		unmet = append(unmet, fmt.Sprintf("have a %s in the party", r.PartySpecies))
+	}
+	if r.PartyType != "" && !partyHas(ctx.Party, p, func(q *BattlePokemon) bool { return q.HasType(r.PartyType) }) {
+		unmet = append(unmet, fmt.Sprintf("have a %s-type in the party", r.PartyType))
+	}
+	if r.PhysicalStats != nil && compareAttackDefense(p) != *r.PhysicalStats {
+		unmet = append(unmet, "have the right Attack/Defense balance")
+	}
+	if r.TradeSpecies != "" {
+		unmet = append(unmet, fmt.Sprintf("be traded for a %s", r.TradeSpecies))
+	}
+	for _, condition := range r.Unsupported {
+		unmet = append(unmet, fmt.Sprintf("meet an untracked condition (%s)", condition))
+	}
+
+	return unmet
+}
+
+func partyHas(party []*BattlePokemon, self *BattlePokemon, match func(*BattlePokemon) bool) bool {
+	for _, q := range party {
+		if q != self && match(q) {
+			return true
 		}
 	}
+	return false
+}
+
+func compareAttackDefense(p *BattlePokemon) int {
+	switch {
+	case p.Stats.Attack > p.Stats.Defense:
+		return 1
+	case p.Stats.Attack < p.Stats.Defense:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// EvolveVia evolves the pokemon along a branch, using up the stone or held item it needed
+func (p *BattlePokemon) EvolveVia(r EvolutionRequirement, newBase pokeapi.Pokemon, ctx EvolutionContext, client pokeapi.Client, rng *RNG) {
+	if r.RequiredStone != "" && ctx.Inventory != nil {
+		ctx.Inventory.EvolutionStones[r.RequiredStone]--
+	}
+	if r.HeldItem != "" {
+		p.HeldItem = ""
+	}
+	p.Evolve(newBase, client, rng)
+}
 
-	return "", 0, ""
+// AutoEvolves is true for evolutions that happen on their own after a level-up
+func (r EvolutionRequirement) AutoEvolves() bool {
+	return r.Trigger == "level-up" && r.RequiredStone == ""
+}
+
+func (r EvolutionRequirement) String() string {
+	var conditions []string
+	if r.RequiredStone != "" {
+		conditions = append(conditions, "use "+r.RequiredStone)
+	}
+	if r.Trigger == "trade" {
+		conditions = append(conditions, "trade")
+	} else if r.Trigger != "" && r.Trigger != "level-up" && r.Trigger != "use-item" {
+		conditions = append(conditions, r.Trigger)
+	}
+	if r.RequiredLevel > 0 {
+		conditions = append(conditions, fmt.Sprintf("level %d+", r.RequiredLevel))
+	}
+	if r.MinFriendship > 0 {
+		conditions = append(conditions, fmt.Sprintf("friendship %d+", r.MinFriendship))
+	}
+	if r.TimeOfDay != "" {
+		conditions = append(conditions, "during "+r.TimeOfDay)
+	}
+	if r.HeldItem != "" {
+		conditions = append(conditions, "holding "+r.HeldItem)
+	}
+	if r.KnownMove != "" {
+		conditions = append(conditions, "knowing "+r.KnownMove)
+	}
+	if r.KnownMoveType != "" {
+		conditions = append(conditions, "knowing a "+r.KnownMoveType+" move")
+	}
+	if r.Location != "" {
+		conditions = append(conditions, "at "+r.Location)
+	}
+	if r.Gender != "" {
+		conditions = append(conditions, r.Gender+" only")
+	}
+	if r.PartySpecies != "" {
+		conditions = append(conditions, r.PartySpecies+" in party")
+	}
+	if r.PartyType != "" {
+		conditions = append(conditions, r.PartyType+"-type in party")
+	}
+	if r.PhysicalStats != nil {
+		switch *r.PhysicalStats {
+		case 1:
+			conditions = append(conditions, "Attack > Defense")
+		case -1:
+			conditions = append(conditions, "Attack < Defense")
+		default:
+			conditions = append(conditions, "Attack = Defense")
+		}
+	}
+	if r.TradeSpecies != "" {
+		conditions = append(conditions, "for a "+r.TradeSpecies)
+	}
+	conditions = append(conditions, r.Unsupported...)
+	if len(conditions) == 0 {
+		conditions = append(conditions, "level up")
+	}
+	return fmt.Sprintf("%s (%s)", r.NextStage, strings.Join(conditions, ", "))
 }
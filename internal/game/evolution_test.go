@@ -0,0 +1,123 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+const eeveeChain = `{
+	"species": {"name": "eevee"},
+	"evolution_details": [],
+	"evolves_to": [
+		{
+			"species": {"name": "vaporeon"},
+			"evolution_details": [{"item": {"name": "water-stone"}, "trigger": {"name": "use-item"}}],
+			"evolves_to": []
+		},
+		{
+			"species": {"name": "jolteon"},
+			"evolution_details": [{"item": {"name": "thunder-stone"}, "trigger": {"name": "use-item"}}],
+			"evolves_to": []
+		},
+		{
+			"species": {"name": "flareon"},
+			"evolution_details": [{"item": {"name": "fire-stone"}, "trigger": {"name": "use-item"}}],
+			"evolves_to": []
+		}
+	]
+}`
+
+func TestFindEvolutions(t *testing.T) {
+	var chain pokeapi.ChainLink
+	if err := json.Unmarshal([]byte(eeveeChain), &chain); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+
+	options := FindEvolutions(chain, "eevee")
+	if len(options) != 3 {
+		t.Fatalf("expected 3 branches, got %d", len(options))
+	}
+
+	expected := []string{"vaporeon", "jolteon", "flareon"}
+	for i, name := range expected {
+		if options[i].NextStage != name {
+			t.Errorf("branch %d: expected %s, got %s", i, name, options[i].NextStage)
+		}
+	}
+
+	if options := FindEvolutions(chain, "vaporeon"); len(options) != 0 {
+		t.Errorf("expected vaporeon to be fully evolved, got %v", options)
+	}
+
+	eevee := &BattlePokemon{Level: 10}
+	ctx := EvolutionContext{Inventory: &PlayerInventory{EvolutionStones: map[string]int{"thunder-stone": 1}}}
+	for _, option := range options {
+		ready := len(option.UnmetConditions(eevee, ctx)) == 0
+		if ready != (option.NextStage == "jolteon") {
+			t.Errorf("%s: unexpected readiness %v", option.NextStage, ready)
+		}
+	}
+}
+
+func TestUnmetConditions(t *testing.T) {
+	minHappiness := 160
+	espeon := newEvolutionRequirement("espeon", pokeapi.EvolutionDetail{
+		Trigger:      pokeapi.NamedAPIResource{Name: "level-up"},
+		MinHappiness: &minHappiness,
+		TimeOfDay:    "day",
+	})
+	gengar := newEvolutionRequirement("gengar", pokeapi.EvolutionDetail{
+		Trigger: pokeapi.NamedAPIResource{Name: "trade"},
+	})
+
+	cases := []struct {
+		name        string
+		requirement EvolutionRequirement
+		pokemon     *BattlePokemon
+		ctx         EvolutionContext
+		ready       bool
+	}{
+		{
+			name:        "friendship too low",
+			requirement: espeon,
+			pokemon:     &BattlePokemon{Friendship: 100},
+			ctx:         EvolutionContext{TimeOfDay: "day"},
+		},
+		{
+			name:        "wrong time of day",
+			requirement: espeon,
+			pokemon:     &BattlePokemon{Friendship: 200},
+			ctx:         EvolutionContext{TimeOfDay: "night"},
+		},
+		{
+			name:        "friendship evolution ready",
+			requirement: espeon,
+			pokemon:     &BattlePokemon{Friendship: 200},
+			ctx:         EvolutionContext{TimeOfDay: "morning"},
+			ready:       true,
+		},
+		{
+			name:        "trade evolution outside a trade",
+			requirement: gengar,
+			pokemon:     &BattlePokemon{},
+		},
+		{
+			name:        "trade evolution during a trade",
+			requirement: gengar,
+			pokemon:     &BattlePokemon{},
+			ctx:         EvolutionContext{Trading: true},
+			ready:       true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			unmet := c.requirement.UnmetConditions(c.pokemon, c.ctx)
+			if (len(unmet) == 0) != c.ready {
+				t.Errorf("expected ready=%v, got unmet conditions %v", c.ready, unmet)
+			}
+		})
+	}
+}
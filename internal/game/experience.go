@@ -0,0 +1,147 @@
+package game
+
+import "github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+
+const MaxLevel = 100
+
+// DefaultGrowthRate is used when the species' growth rate can't be fetched
+const DefaultGrowthRate = "medium"
+
+// ExperienceCurve holds the total XP needed to reach each level (index = level)
+type ExperienceCurve []int
+
+// At returns the total XP needed for a level, clamped to 1..MaxLevel
+func (c ExperienceCurve) At(level int) int {
+	if level < 1 {
+		level = 1
+	}
+	if level > MaxLevel {
+		level = MaxLevel
+	}
+	return c[level]
+}
+
+// LoadExperienceCurve fetches a growth rate from the API.
+// Falls back to the mainline formulas if the API is unreachable.
+func LoadExperienceCurve(client pokeapi.Client, rate string) ExperienceCurve {
+	curve := make(ExperienceCurve, MaxLevel+1)
+	for lvl := 1; lvl <= MaxLevel; lvl++ {
+		curve[lvl] = ExperienceForLevel(rate, lvl)
+	}
+
+	apiRate, err := client.GetGrowthRate(rate)
+	if err != nil {
+		return curve
+	}
+	for _, l := range apiRate.Levels {
+		if l.Level >= 1 && l.Level <= MaxLevel {
+			curve[l.Level] = l.Experience
+		}
+	}
+	return curve
+}
+
+// ExperienceForLevel computes the total XP for a level using the PokeAPI growth rate names
+func ExperienceForLevel(rate string, level int) int {
+	if level <= 1 {
+		return 0
+	}
+	n := level
+	cube := n * n * n
+
+	switch rate {
+	case "slow":
+		return 5 * cube / 4
+	case "fast":
+		return 4 * cube / 5
+	case "medium-slow":
+		return 6*cube/5 - 15*n*n + 100*n - 140
+	case "slow-then-very-fast": // Erratic
+		switch {
+		case n < 50:
+			return cube * (100 - n) / 50
+		case n < 68:
+			return cube * (150 - n) / 100
+		case n < 98:
+			return cube * ((1911 - 10*n) / 3) / 500
+		default:
+			return cube * (160 - n) / 100
+		}
+	case "fast-then-very-slow": // Fluctuating
+		switch {
+		case n < 15:
+			return cube * ((n+1)/3 + 24) / 50
+		case n < 36:
+			return cube * (n + 14) / 50
+		default:
+			return cube * (n/2 + 32) / 50
+		}
+	default: // "medium" (Medium Fast)
+		return cube
+	}
+}
+
+// FetchGrowthRate looks up the species' growth rate name
+func FetchGrowthRate(client pokeapi.Client, speciesName string) string {
+	species, err := client.GetPokemonSpecies(speciesName)
+	if err != nil || species.GrowthRate.Name == "" {
+		return DefaultGrowthRate
+	}
+	return species.GrowthRate.Name
+}
+
+// SetLevel puts the pokemon at the start of a level on its curve
+func (p *BattlePokemon) SetLevel(level int, curve ExperienceCurve) {
+	p.Level = level
+	p.LevelXP = curve.At(level)
+	p.XP = p.LevelXP
+	p.NextLevelXP = curve.At(level + 1)
+}
+
+// EnsureGrowthRate upgrades pokemon from older saves, where XP was
+// progress within the level, to total XP on their species' curve.
+func (p *BattlePokemon) EnsureGrowthRate(client pokeapi.Client) ExperienceCurve {
+	if p.GrowthRate != "" {
+		return LoadExperienceCurve(client, p.GrowthRate)
+	}
+
+	p.GrowthRate = FetchGrowthRate(client, p.Base.Name)
+	curve := LoadExperienceCurve(client, p.GrowthRate)
+
+	progress := p.XP
+	p.LevelXP = curve.At(p.Level)
+	p.XP = p.LevelXP + progress
+	p.NextLevelXP = curve.At(p.Level + 1)
+	return curve
+}
+
+// CanLevelUp reports whether the pokemon has enough XP for its next level
+func (p *BattlePokemon) CanLevelUp() bool {
+	return p.Level < MaxLevel && p.XP >= p.NextLevelXP
+}
+
+// LevelUp raises the level by one, keeping the HP already lost
+func (p *BattlePokemon) LevelUp(curve ExperienceCurve) {
+	oldMaxHP := p.Stats.MaxHP
+
+	p.Level++
+	p.LevelXP = curve.At(p.Level)
+	p.NextLevelXP = curve.At(p.Level + 1)
+	p.RecalculateStats()
+
+	if p.Status != StatusFainted {
+		p.Stats.HP += p.Stats.MaxHP - oldMaxHP
+	}
+	p.AdjustFriendship(FriendshipLevelUp)
+	if p.Level >= MaxLevel {
+		p.XP = p.LevelXP
+	}
+}
+
+// ExperienceProgress returns XP earned in the current level and XP the level needs
+func (p *BattlePokemon) ExperienceProgress() (current, needed int) {
+	if p.Level >= MaxLevel {
+		return 0, 0
+	}
+	return p.XP - p.LevelXP, p.NextLevelXP - p.LevelXP
+}
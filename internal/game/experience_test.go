@@ -0,0 +1,61 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExperienceForLevel(t *testing.T) {
+	cases := []struct {
+		rate     string
+		level    int
+		expected int
+	}{
+		{rate: "medium", level: 1, expected: 0},
+		{rate: "medium", level: 100, expected: 1000000},
+		{rate: "slow", level: 100, expected: 1250000},
+		{rate: "fast", level: 100, expected: 800000},
+		{rate: "medium-slow", level: 5, expected: 135},
+		{rate: "medium-slow", level: 100, expected: 1059860},
+		{rate: "slow-then-very-fast", level: 100, expected: 600000},
+		{rate: "fast-then-very-slow", level: 100, expected: 1640000},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			actual := ExperienceForLevel(c.rate, c.level)
+			if actual != c.expected {
+				t.Errorf("%s level %d: got %d, expected %d", c.rate, c.level, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestLevelUpMultipleLevels(t *testing.T) {
+	curve := make(ExperienceCurve, MaxLevel+1)
+	for lvl := 1; lvl <= MaxLevel; lvl++ {
+		curve[lvl] = ExperienceForLevel("medium", lvl)
+	}
+
+	p := &BattlePokemon{GrowthRate: "medium"}
+	p.SetLevel(5, curve)
+	p.RecalculateStats()
+	p.Stats.HP = p.Stats.MaxHP - 3
+
+	// Enough XP to land exactly on level 8
+	p.XP = curve.At(8)
+	for p.CanLevelUp() {
+		p.LevelUp(curve)
+	}
+
+	if p.Level != 8 {
+		t.Errorf("expected level 8, got %d", p.Level)
+	}
+	if p.Stats.HP != p.Stats.MaxHP-3 {
+		t.Errorf("expected damage to carry over, got %d/%d", p.Stats.HP, p.Stats.MaxHP)
+	}
+	current, needed := p.ExperienceProgress()
+	if current != 0 || needed != curve.At(9)-curve.At(8) {
+		t.Errorf("unexpected progress %d/%d", current, needed)
+	}
+}
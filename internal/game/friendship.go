@@ -0,0 +1,129 @@
+package game
+
+import (
+	"encoding/json"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+const (
+	MaxFriendship     = 255
+	DefaultFriendship = 70
+)
+
+// FriendshipEvent is something that makes a pokemon like its trainer more (or less)
+type FriendshipEvent int
+
+const (
+	FriendshipLevelUp FriendshipEvent = iota
+	FriendshipBattle
+	FriendshipFainted
+	FriendshipHealed
+	FriendshipWalk
+)
+
+// FriendshipWalkSteps is how many steps walking together it takes for the party to grow closer
+const FriendshipWalkSteps = 128
+
+// Gains per event for friendship below 100, 100-199 and 200+.
+// Pokemon warm up quickly at first and slowly once they're already close.
+var friendshipChanges = map[FriendshipEvent][3]int{
+	FriendshipLevelUp: {5, 3, 2},
+	FriendshipBattle:  {3, 2, 1},
+	FriendshipFainted: {-1, -1, -1},
+	FriendshipHealed:  {1, 1, 0},
+	FriendshipWalk:    {1, 1, 1},
+}
+
+// AdjustFriendship applies an event and keeps friendship within 0..MaxFriendship
+func (p *BattlePokemon) AdjustFriendship(event FriendshipEvent) {
+	band := 0
+	switch {
+	case p.Friendship >= 200:
+		band = 2
+	case p.Friendship >= 100:
+		band = 1
+	}
+
+	p.Friendship += friendshipChanges[event][band]
+	if p.Friendship > MaxFriendship {
+		p.Friendship = MaxFriendship
+	}
+	if p.Friendship < 0 {
+		p.Friendship = 0
+	}
+}
+
+// WalkTogether counts a step taken with the party, bringing everyone a little
+// closer every FriendshipWalkSteps. It returns the new step count.
+func WalkTogether(party []*BattlePokemon, steps int) int {
+	steps++
+	if steps%FriendshipWalkSteps == 0 {
+		for _, p := range party {
+			p.AdjustFriendship(FriendshipWalk)
+		}
+	}
+	return steps
+}
+
+// UnmarshalJSON notes pokemon saved before friendship existed, for EnsureFriendship
+func (p *BattlePokemon) UnmarshalJSON(data []byte) error {
+	type plain BattlePokemon
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	var saved struct{ Friendship *int }
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	p.friendshipUnset = saved.Friendship == nil
+	return nil
+}
+
+// SpeciesFetcher looks up a species, like *pokeapi.Client does
+type SpeciesFetcher interface {
+	GetPokemonSpecies(name string) (pokeapi.PokemonSpecies, error)
+}
+
+// EnsureFriendship upgrades pokemon from older saves, which would otherwise load
+// at 0, to their species' base friendship, or DefaultFriendship if it can't be found.
+func (p *BattlePokemon) EnsureFriendship(species SpeciesFetcher) {
+	if !p.friendshipUnset {
+		return
+	}
+	p.friendshipUnset = false
+	p.Friendship = DefaultFriendship
+	if s, err := species.GetPokemonSpecies(p.Base.Name); err == nil {
+		p.Friendship = s.BaseHappiness
+	}
+}
+
+// FriendshipDescription is the flavour text shown when inspecting a pokemon
+func (p *BattlePokemon) FriendshipDescription() string {
+	switch {
+	case p.Friendship >= 255:
+		return "It adores you!"
+	case p.Friendship >= 200:
+		return "It's very friendly toward you."
+	case p.Friendship >= 150:
+		return "It's quite friendly toward you."
+	case p.Friendship >= 100:
+		return "It's warming up to you."
+	case p.Friendship >= 50:
+		return "It's not used to you yet."
+	default:
+		return "It doesn't seem to like you."
+	}
+}
+
+// movePower handles moves whose power comes from friendship
+func movePower(attacker *BattlePokemon, move *Move) int {
+	switch move.Name {
+	case "return":
+		return max(attacker.Friendship*10/25, 1)
+	case "frustration":
+		return max((MaxFriendship-attacker.Friendship)*10/25, 1)
+	default:
+		return move.Power
+	}
+}
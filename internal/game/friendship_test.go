@@ -0,0 +1,106 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+func TestAdjustFriendship(t *testing.T) {
+	cases := []struct {
+		name     string
+		start    int
+		event    FriendshipEvent
+		expected int
+	}{
+		{name: "level up while low", start: 70, event: FriendshipLevelUp, expected: 75},
+		{name: "level up while mid", start: 150, event: FriendshipLevelUp, expected: 153},
+		{name: "level up while high", start: 200, event: FriendshipLevelUp, expected: 202},
+		{name: "capped at max", start: 254, event: FriendshipLevelUp, expected: MaxFriendship},
+		{name: "fainting", start: 70, event: FriendshipFainted, expected: 69},
+		{name: "never negative", start: 0, event: FriendshipFainted, expected: 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := &BattlePokemon{Friendship: c.start}
+			p.AdjustFriendship(c.event)
+			if p.Friendship != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, p.Friendship)
+			}
+		})
+	}
+}
+
+func TestWalkTogether(t *testing.T) {
+	p := &BattlePokemon{Friendship: DefaultFriendship}
+	party := []*BattlePokemon{p}
+
+	steps := 0
+	for i := 1; i < FriendshipWalkSteps; i++ {
+		steps = WalkTogether(party, steps)
+	}
+	if p.Friendship != DefaultFriendship {
+		t.Errorf("expected no change before %d steps, got %d", FriendshipWalkSteps, p.Friendship)
+	}
+	steps = WalkTogether(party, steps)
+	if steps != FriendshipWalkSteps || p.Friendship != DefaultFriendship+1 {
+		t.Errorf("expected +1 after %d steps, got %d after %d", FriendshipWalkSteps, p.Friendship, steps)
+	}
+	for i := 0; i < 50; i++ {
+		steps = WalkTogether(party, steps)
+	}
+	if p.Friendship != DefaultFriendship+1 {
+		t.Errorf("expected a 50 step walk not to count again yet, got %d", p.Friendship)
+	}
+}
+
+// stubSpecies answers species lookups from a map, failing for anything else
+type stubSpecies map[string]pokeapi.PokemonSpecies
+
+func (s stubSpecies) GetPokemonSpecies(name string) (pokeapi.PokemonSpecies, error) {
+	species, ok := s[name]
+	if !ok {
+		return pokeapi.PokemonSpecies{}, fmt.Errorf("no species %s", name)
+	}
+	return species, nil
+}
+
+func TestEnsureFriendship(t *testing.T) {
+	species := stubSpecies{"clefairy": {BaseHappiness: 140}}
+
+	cases := []struct {
+		saved    string
+		expected int
+	}{
+		{saved: `{"Base": {"name": "clefairy"}, "Level": 5}`, expected: 140}, // Saved before friendship
+		{saved: `{"Base": {"name": "missingno"}, "Level": 5}`, expected: DefaultFriendship},
+		{saved: `{"Base": {"name": "clefairy"}, "Level": 5, "Friendship": 0}`, expected: 0},
+		{saved: `{"Base": {"name": "clefairy"}, "Level": 5, "Friendship": 180}`, expected: 180},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			var p BattlePokemon
+			if err := json.Unmarshal([]byte(c.saved), &p); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if p.Base.Name == "" || p.Level != 5 {
+				t.Errorf("expected the rest of the pokemon to load, got %+v", p)
+			}
+			p.EnsureFriendship(species)
+			if p.Friendship != c.expected {
+				t.Errorf("expected %d friendship, got %d", c.expected, p.Friendship)
+			}
+
+			// Only the first load is upgraded
+			p.Friendship = 1
+			p.EnsureFriendship(species)
+			if p.Friendship != 1 {
+				t.Errorf("expected friendship to be left alone once upgraded, got %d", p.Friendship)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed data/gyms.json
+var gymData []byte
+
+// BaseObedienceLevel is the highest level that obeys a trainer with no badges
+const BaseObedienceLevel = 10
+
+// Gym is one stop on the path to the championship, defined in data/gyms.json
+type Gym struct {
+	Number         int      `json:"number"` // Gyms must be beaten in order
+	City           string   `json:"city"`
+	Type           string   `json:"type"`
+	Badge          string   `json:"badge"`
+	ObedienceLevel int      `json:"obedience_level"` // Highest level that obeys once the badge is earned
+	Unlocks        []string `json:"unlocks,omitempty"`
+	Leader         Trainer  `json:"leader"`
+}
+
+// Badges holds the badges the player has earned, by badge name
+type Badges map[string]bool
+
+// LoadGyms returns the gyms bundled with the game, in order
+func LoadGyms() ([]Gym, error) {
+	return ParseGyms(gymData)
+}
+
+// ParseGyms decodes and checks a gym data file
+func ParseGyms(data []byte) ([]Gym, error) {
+	var gyms []Gym
+	if err := json.Unmarshal(data, &gyms); err != nil {
+		return nil, fmt.Errorf("invalid gym data: %w", err)
+	}
+
+	lastObedience := BaseObedienceLevel
+	for i, g := range gyms {
+		if g.Number != i+1 {
+			return nil, fmt.Errorf("gym %d is listed in position %d", g.Number, i+1)
+		}
+		if g.Badge == "" || g.Type == "" {
+			return nil, fmt.Errorf("gym %d needs a badge and a type", g.Number)
+		}
+		if g.ObedienceLevel < lastObedience || g.ObedienceLevel > MaxLevel {
+			return nil, fmt.Errorf("gym %d's obedience level must be between %d and %d", g.Number, lastObedience, MaxLevel)
+		}
+		lastObedience = g.ObedienceLevel
+		if err := g.Leader.validate(); err != nil {
+			return nil, fmt.Errorf("gym %d: %w", g.Number, err)
+		}
+	}
+	return gyms, nil
+}
+
+// BadgeLabel is the badge's display name, e.g. "Boulder Badge"
+func (g Gym) BadgeLabel() string {
+	return capitalizeWords(g.Badge) + " Badge"
+}
+
+// Has reports whether a gym's badge has been earned
+func (b Badges) Has(g Gym) bool {
+	return b[g.Badge]
+}
+
+// NextGym is the first gym whose badge the player doesn't have yet
+func NextGym(gyms []Gym, badges Badges) (Gym, bool) {
+	for _, g := range gyms {
+		if !badges.Has(g) {
+			return g, true
+		}
+	}
+	return Gym{}, false
+}
+
+// ObedienceLevel is the highest level that obeys the player
+func ObedienceLevel(gyms []Gym, badges Badges) int {
+	level := BaseObedienceLevel
+	for _, g := range gyms {
+		if badges.Has(g) && g.ObedienceLevel > level {
+			level = g.ObedienceLevel
+		}
+	}
+	return level
+}
+
+// UnlockingGym finds the gym whose badge is needed to buy an item.
+// Items no gym unlocks are always for sale.
+func UnlockingGym(gyms []Gym, item string) (Gym, bool) {
+	for _, g := range gyms {
+		for _, unlocked := range g.Unlocks {
+			if unlocked == item {
+				return g, true
+			}
+		}
+	}
+	return Gym{}, false
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLoadGyms(t *testing.T) {
+	gyms, err := LoadGyms()
+	if err != nil {
+		t.Fatalf("bundled gym data is invalid: %v", err)
+	}
+	if len(gyms) != 8 {
+		t.Fatalf("expected eight gyms, got %d", len(gyms))
+	}
+	types := make(map[string]bool)
+	for _, g := range gyms {
+		if types[g.Type] {
+			t.Errorf("two gyms share the %s type", g.Type)
+		}
+		types[g.Type] = true
+	}
+	if gyms[0].BadgeLabel() != "Boulder Badge" {
+		t.Errorf("unexpected first badge %q", gyms[0].BadgeLabel())
+	}
+}
+
+func TestBadgeProgression(t *testing.T) {
+	gyms, err := LoadGyms()
+	if err != nil {
+		t.Fatalf("LoadGyms: %v", err)
+	}
+
+	cases := []struct {
+		badges    Badges
+		next      int // 0 when every badge is earned
+		obedience int
+	}{
+		{badges: Badges{}, next: 1, obedience: BaseObedienceLevel},
+		{badges: Badges{"boulder": true}, next: 2, obedience: 20},
+		{badges: Badges{"boulder": true, "cascade": true, "thunder": true}, next: 4, obedience: 40},
+		{badges: Badges{"boulder": true, "cascade": true, "thunder": true, "rainbow": true, "soul": true, "marsh": true, "volcano": true, "earth": true}, next: 0, obedience: MaxLevel},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			next, ok := NextGym(gyms, c.badges)
+			if !ok {
+				next.Number = 0
+			}
+			if next.Number != c.next {
+				t.Errorf("expected gym %d next, got %d", c.next, next.Number)
+			}
+			if actual := ObedienceLevel(gyms, c.badges); actual != c.obedience {
+				t.Errorf("expected obedience level %d, got %d", c.obedience, actual)
+			}
+		})
+	}
+
+	if gym, ok := UnlockingGym(gyms, "greatball"); !ok || gym.Number != 1 {
+		t.Errorf("expected the first gym to unlock great balls")
+	}
+	if _, ok := UnlockingGym(gyms, "pokeball"); ok {
+		t.Errorf("poke balls should always be for sale")
+	}
+}
+
+func TestParseGymsRejectsOutOfOrder(t *testing.T) {
+	data := `[{"number": 2, "city": "A", "type": "rock", "badge": "a", "obedience_level": 20,
+		"leader": {"id": "a", "name": "A", "team": [{"species": "onix", "level": 10}]}}]`
+	if _, err := ParseGyms([]byte(data)); err == nil {
+		t.Errorf("expected an error for a gym listed out of order")
+	}
+}
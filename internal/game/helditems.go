@@ -0,0 +1,168 @@
+package game
+
+import "fmt"
+
+// typeBoostItems power up moves of one type by 20%
+var typeBoostItems = map[string]string{
+	"silk-scarf":     "normal",
+	"charcoal":       "fire",
+	"mystic-water":   "water",
+	"miracle-seed":   "grass",
+	"magnet":         "electric",
+	"never-melt-ice": "ice",
+	"black-belt":     "fighting",
+	"poison-barb":    "poison",
+	"soft-sand":      "ground",
+	"sharp-beak":     "flying",
+	"twisted-spoon":  "psychic",
+	"silver-powder":  "bug",
+	"hard-stone":     "rock",
+	"spell-tag":      "ghost",
+	"dragon-fang":    "dragon",
+	"black-glasses":  "dark",
+	"metal-coat":     "steel",
+}
+
+// berryCures maps status-curing berries to the status they heal (StatusNone = any)
+var berryCures = map[string]StatusID{
+	"rawst-berry": StatusBurn,
+	"pecha-berry": StatusPoison,
+	"cheri-berry": StatusParalysis,
+	"lum-berry":   StatusNone,
+}
+
+// Items that only matter for evolution but can still be held
+var evolutionHoldItems = map[string]bool{
+	"kings-rock":   true,
+	"razor-claw":   true,
+	"razor-fang":   true,
+	"dragon-scale": true,
+	"up-grade":     true,
+	"oval-stone":   true,
+	"electirizer":  true,
+	"magmarizer":   true,
+	"protector":    true,
+	"reaper-cloth": true,
+}
+
+// IsHoldItem reports whether an item can be given to a pokemon to hold
+func IsHoldItem(name string) bool {
+	if _, ok := typeBoostItems[name]; ok {
+		return true
+	}
+	if _, ok := berryCures[name]; ok {
+		return true
+	}
+	switch name {
+	case "leftovers", "oran-berry", "sitrus-berry", "choice-band", "choice-scarf", "smoke-ball":
+		return true
+	}
+	return evolutionHoldItems[name]
+}
+
+// HeldItemDescription explains an item's battle effect
+func HeldItemDescription(name string) string {
+	if t, ok := typeBoostItems[name]; ok {
+		return fmt.Sprintf("Boosts %s-type moves by 20%%", t)
+	}
+	if status, ok := berryCures[name]; ok {
+		if status == StatusNone {
+			return "Cures any status condition once"
+		}
+		return fmt.Sprintf("Cures %s once", status)
+	}
+	switch name {
+	case "leftovers":
+		return "Restores 1/16 of max HP every turn"
+	case "oran-berry":
+		return "Restores 10 HP once at half HP or less"
+	case "sitrus-berry":
+		return "Restores 1/4 of max HP once at half HP or less"
+	case "choice-band":
+		return "Boosts Attack by 50% but locks into one move"
+	case "choice-scarf":
+		return "Boosts Speed by 50% but locks into one move"
+	case "smoke-ball":
+		return "Guarantees escape from wild battles"
+	}
+	if evolutionHoldItems[name] {
+		return "Needed by some Pokemon to evolve"
+	}
+	return "No battle effect"
+}
+
+// effectiveAttack applies held item stat boosts
+func effectiveAttack(p *BattlePokemon) float64 {
+	atk := float64(p.Stats.Attack)
+	if p.HeldItem == "choice-band" {
+		atk *= 1.5
+	}
+	return atk
+}
+
+// effectiveSpeed applies held item stat boosts
+func effectiveSpeed(p *BattlePokemon) int {
+	if p.HeldItem == "choice-scarf" {
+		return p.Stats.Speed * 3 / 2
+	}
+	return p.Stats.Speed
+}
+
+func heldItemDamageMultiplier(attacker *BattlePokemon, move *Move) float64 {
+	if t, ok := typeBoostItems[attacker.HeldItem]; ok && t == move.Type {
+		return 1.2
+	}
+	return 1.0
+}
+
+// isChoiceItem reports whether the holder gets locked into its first move
+func isChoiceItem(name string) bool {
+	return name == "choice-band" || name == "choice-scarf"
+}
+
+// checkHeldBerry eats a berry if its trigger is met. Call after damage or a status change.
+func checkHeldBerry(p *BattlePokemon) []Event {
+	if p.Status == StatusFainted || p.Stats.HP <= 0 {
+		return nil
+	}
+
+	switch p.HeldItem {
+	case "oran-berry", "sitrus-berry":
+		if p.Stats.HP*2 > p.Stats.MaxHP {
+			return nil
+		}
+		heal := 10
+		if p.HeldItem == "sitrus-berry" {
+			heal = max(p.Stats.MaxHP/4, 1)
+		}
+		before := p.Stats.HP
+		p.Stats.HP = min(p.Stats.HP+heal, p.Stats.MaxHP)
+		berry := p.HeldItem
+		p.HeldItem = ""
+		return []Event{{Kind: EventHeal, Target: p.Nickname, Item: berry, Amount: p.Stats.HP - before, HP: p.Stats.HP, MaxHP: p.Stats.MaxHP}}
+	}
+
+	if cures, ok := berryCures[p.HeldItem]; ok && p.Status != StatusNone {
+		if cures == StatusNone || cures == p.Status {
+			event := Event{Kind: EventCure, Target: p.Nickname, Item: p.HeldItem, Status: p.Status}
+			p.Status = StatusNone
+			p.HeldItem = ""
+			return []Event{event}
+		}
+	}
+	return nil
+}
+
+// heldItemEndOfTurn applies effects that trigger at the end of every turn
+func heldItemEndOfTurn(p *BattlePokemon) []Event {
+	if p.Status == StatusFainted || p.Stats.HP <= 0 {
+		return nil
+	}
+
+	if p.HeldItem == "leftovers" && p.Stats.HP < p.Stats.MaxHP {
+		before := p.Stats.HP
+		p.Stats.HP = min(p.Stats.HP+max(p.Stats.MaxHP/16, 1), p.Stats.MaxHP)
+		return []Event{{Kind: EventHeal, Target: p.Nickname, Item: p.HeldItem, Amount: p.Stats.HP - before, HP: p.Stats.HP, MaxHP: p.Stats.MaxHP}}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package game
+
+import "testing"
+
+func TestCheckHeldBerry(t *testing.T) {
+	cases := []struct {
+		name       string
+		item       string
+		hp         int
+		status     StatusID
+		expectedHP int
+		consumed   bool
+		cured      bool
+	}{
+		{name: "sitrus above half HP", item: "sitrus-berry", hp: 60, expectedHP: 60},
+		{name: "sitrus at half HP", item: "sitrus-berry", hp: 50, expectedHP: 75, consumed: true},
+		{name: "oran caps at max", item: "oran-berry", hp: 45, expectedHP: 55, consumed: true},
+		{name: "rawst ignores poison", item: "rawst-berry", hp: 100, status: StatusPoison, expectedHP: 100},
+		{name: "lum cures anything", item: "lum-berry", hp: 100, status: StatusParalysis, expectedHP: 100, consumed: true, cured: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := &BattlePokemon{HeldItem: c.item, Status: c.status, Stats: Stats{HP: c.hp, MaxHP: 100}}
+			checkHeldBerry(p)
+			if p.Stats.HP != c.expectedHP {
+				t.Errorf("expected %d HP, got %d", c.expectedHP, p.Stats.HP)
+			}
+			if (p.HeldItem == "") != c.consumed {
+				t.Errorf("expected consumed=%v, still holding %q", c.consumed, p.HeldItem)
+			}
+			if c.cured && p.Status != StatusNone {
+				t.Errorf("expected status to be cured, got %s", p.Status)
+			}
+		})
+	}
+}
+
+func TestLeftovers(t *testing.T) {
+	p := &BattlePokemon{HeldItem: "leftovers", Stats: Stats{HP: 50, MaxHP: 160}}
+	heldItemEndOfTurn(p)
+	if p.Stats.HP != 60 {
+		t.Errorf("expected 60 HP after Leftovers, got %d", p.Stats.HP)
+	}
+}
@@ -1,7 +1,7 @@
 package game
 
 import (
-	"math/rand"
+	"strings"
 
 	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
 )
@@ -15,6 +15,8 @@ const (
 	StatusPoison
 	StatusParalysis
 	StatusFainted
+	StatusSleep
+	StatusFreeze
 )
 
 // Stats structure
@@ -35,6 +37,9 @@ type Move struct {
 	StatusEffect StatusID
 	MaxPP        int
 	CurrentPP    int
+	Priority     int    // Higher goes first regardless of speed
+	Target       string // PokeAPI move target, e.g. "selected-pokemon" or "all-opponents"
+	DamageClass  string // "physical", "special" or "status"
 }
 
 // BattlePokemon is a dynamic instance of a Pokemon
@@ -42,18 +47,51 @@ type BattlePokemon struct {
 	Base        pokeapi.Pokemon
 	Nickname    string
 	Level       int
-	XP          int
-	NextLevelXP int
+	XP          int // Total XP earned
+	LevelXP     int // Total XP at the start of the current level
+	NextLevelXP int // Total XP needed for the next level
+	GrowthRate  string
 	Stats       Stats
 	Status      StatusID
 	Moves       []Move
 	Nature      string // e.g., "Adamant" (+Atk, -SpAtk)
+	Gender      string // "male", "female" or "" for genderless
+	Friendship  int
+	HeldItem    string
+	Ability     string // e.g. "drizzle"
+	ChoiceLock  string `json:"-"` // Move a Choice item has locked this battle
+	CaptureRate int
+	CaughtBall  string // Ball it was caught in, empty for starters
+
+	friendshipUnset bool // Loaded from a save before friendship, see EnsureFriendship
 }
 
 type EvolutionRequirement struct {
 	NextStage     string // The species name to evolve into
+	Trigger       string // "level-up", "use-item", "trade", ...
 	RequiredLevel int    // Minimum level
 	RequiredStone string // The specific stone name (e.g., "fire-stone")
+	MinFriendship int
+	TimeOfDay     string // "day", "night" or "dusk"
+	HeldItem      string // Consumed when evolving
+	KnownMove     string
+	KnownMoveType string
+	Location      string
+	Gender        string
+	PartySpecies  string
+	PartyType     string
+	PhysicalStats *int // 1 Atk > Def, 0 equal, -1 Atk < Def
+	TradeSpecies  string
+	Unsupported   []string // Conditions the game doesn't track
+}
+
+// EvolutionContext is the game state evolution conditions are checked against
+type EvolutionContext struct {
+	Inventory *PlayerInventory
+	Party     []*BattlePokemon
+	Location  string
+	TimeOfDay string
+	Trading   bool
 }
 
 // PlayerInventory holds items
@@ -64,45 +102,56 @@ type PlayerInventory struct {
 	Pokeballs       int
 	Greatballs      int
 	Ultraballs      int
+	Masterballs     int
+	Balls           map[string]int // Specialty balls like the Net Ball
 	Revives         int
 	EvolutionStones map[string]int
+	HeldItems       map[string]int  // Items that can be given to a pokemon
+	ExpShare        bool            // Owns the Exp. Share
+	ExpShareOn      bool            // Benched party members get XP while on
+	Repels          map[string]int  // Unused repels by kind
+	RepelSteps      int             // Steps left on the active repel
+	Rods            map[string]bool // Fishing rods owned
 }
 
 // In internal/game/models.go
 
-// Update signature to accept Client
-func NewBattlePokemon(base pokeapi.Pokemon, level int, client pokeapi.Client) (*BattlePokemon, error) {
+// Update signature to accept Client and the RNG for gender and moves
+func NewBattlePokemon(base pokeapi.Pokemon, level int, client pokeapi.Client, rng *RNG) (*BattlePokemon, error) {
 	bp := &BattlePokemon{
-		Base:     base,
-		Nickname: base.Name,
-		Level:    level,
-		XP:       0,
-		Status:   StatusNone,
-		Stats:    Stats{},
+		Base:       base,
+		Nickname:   base.Name,
+		Status:     StatusNone,
+		Stats:      Stats{},
+		GrowthRate: DefaultGrowthRate,
+		Friendship: DefaultFriendship,
+		Ability:    DefaultAbility(base),
+	}
+
+	if species, err := client.GetPokemonSpecies(base.Name); err == nil {
+		if species.GrowthRate.Name != "" {
+			bp.GrowthRate = species.GrowthRate.Name
+		}
+		bp.Gender = RollGender(species.GenderRate, rng)
+		bp.Friendship = species.BaseHappiness
+		bp.CaptureRate = species.CaptureRate
 	}
 
-	bp.NextLevelXP = level * level * 10
+	bp.SetLevel(level, LoadExperienceCurve(client, bp.GrowthRate))
 	bp.RecalculateStats()
 	bp.Stats.HP = bp.Stats.MaxHP
 
 	// 1. Pick a random move from the list of possible moves
 	if len(base.Moves) > 0 {
 		// Simple logic: Pick a random one.
-		randIdx := rand.Intn(len(base.Moves))
+		randIdx := rng.Intn(len(base.Moves))
 		moveName := base.Moves[randIdx].Move.Name
 
 		// 2. Fetch the details
 		apiMove, err := client.GetMove(moveName)
 		if err == nil {
 			// Convert API Move to Game Move
-			gameMove := Move{
-				Name:      apiMove.Name,
-				Type:      apiMove.Type.Name,
-				Power:     apiMove.Power,
-				Accuracy:  apiMove.Accuracy,
-				MaxPP:     apiMove.PP,
-				CurrentPP: apiMove.PP,
-			}
+			gameMove := NewMove(apiMove)
 			bp.Moves = append(bp.Moves, gameMove)
 		}
 	}
@@ -115,6 +164,42 @@ func NewBattlePokemon(base pokeapi.Pokemon, level int, client pokeapi.Client) (*
 	return bp, nil
 }
 
+// NewMove converts a move from the API with full PP
+func NewMove(apiMove pokeapi.Move) Move {
+	return Move{
+		Name:        apiMove.Name,
+		Type:        apiMove.Type.Name,
+		Power:       apiMove.Power,
+		Accuracy:    apiMove.Accuracy,
+		MaxPP:       apiMove.PP,
+		CurrentPP:   apiMove.PP,
+		Priority:    apiMove.Priority,
+		Target:      apiMove.Target.Name,
+		DamageClass: apiMove.DamageClass.Name,
+	}
+}
+
+// DefaultAbility is the species' first regular ability, skipping hidden ones
+func DefaultAbility(base pokeapi.Pokemon) string {
+	for _, a := range base.Abilities {
+		if !a.IsHidden {
+			return a.Ability.Name
+		}
+	}
+	return ""
+}
+
+// RollGender picks a gender from the species' female chance in eighths
+func RollGender(genderRate int, rng *RNG) string {
+	if genderRate < 0 {
+		return ""
+	}
+	if rng.Intn(8) < genderRate {
+		return "female"
+	}
+	return "male"
+}
+
 func (p *BattlePokemon) RecalculateStats() {
 	// Formula: ((Base * 2 * Level) / 100) + 5
 	// HP Formula: ((Base * 2 * Level) / 100) + Level + 10
@@ -151,6 +236,43 @@ func (p *BattlePokemon) GenerateMoves() {
 	}
 }
 
+// KnowsMove compares against API move names like "ancient-power"
+func (p *BattlePokemon) KnowsMove(name string) bool {
+	for _, m := range p.Moves {
+		if strings.EqualFold(m.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *BattlePokemon) KnowsMoveOfType(moveType string) bool {
+	for _, m := range p.Moves {
+		if m.Type == moveType {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeNames lists the pokemon's types, e.g. ["grass", "poison"]
+func (p *BattlePokemon) TypeNames() []string {
+	names := make([]string, 0, len(p.Base.Types))
+	for _, t := range p.Base.Types {
+		names = append(names, t.Type.Name)
+	}
+	return names
+}
+
+func (p *BattlePokemon) HasType(typeName string) bool {
+	for _, t := range p.Base.Types {
+		if t.Type.Name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *BattlePokemon) HealFull() {
 	p.Stats.HP = p.Stats.MaxHP
 	p.Status = StatusNone
@@ -171,12 +293,16 @@ func (s StatusID) String() string {
 		return "PAR"
 	case StatusFainted:
 		return "FNT"
+	case StatusSleep:
+		return "SLP"
+	case StatusFreeze:
+		return "FRZ"
 	default:
 		return "???"
 	}
 }
 
-func (p *BattlePokemon) Evolve(newBase pokeapi.Pokemon, client pokeapi.Client) {
+func (p *BattlePokemon) Evolve(newBase pokeapi.Pokemon, client pokeapi.Client, rng *RNG) {
 	oldMaxHP := p.Stats.MaxHP
 	oldSpeciesName := p.Base.Name // Store the old name (e.g., "charmander")
 
@@ -188,6 +314,9 @@ func (p *BattlePokemon) Evolve(newBase pokeapi.Pokemon, client pokeapi.Client) {
 
 	// NOW we update the base to the new species
 	p.Base = newBase
+	if ability := DefaultAbility(newBase); ability != "" {
+		p.Ability = ability
+	}
 
 	// 2. Recalculate Stats
 	p.RecalculateStats()
@@ -202,18 +331,11 @@ func (p *BattlePokemon) Evolve(newBase pokeapi.Pokemon, client pokeapi.Client) {
 
 	// 4. Learn a New Move
 	if len(newBase.Moves) > 0 {
-		randomIndex := rand.Intn(len(newBase.Moves))
+		randomIndex := rng.Intn(len(newBase.Moves))
 		moveName := newBase.Moves[randomIndex].Move.Name
 		apiMove, err := client.GetMove(moveName)
 		if err == nil {
-			newMove := Move{
-				Name:      apiMove.Name,
-				Type:      apiMove.Type.Name,
-				Power:     apiMove.Power,
-				Accuracy:  apiMove.Accuracy,
-				MaxPP:     apiMove.PP,
-				CurrentPP: apiMove.PP,
-			}
+			newMove := NewMove(apiMove)
 
 			hasMove := false
 			for _, m := range p.Moves {
@@ -231,3 +353,10 @@ func (p *BattlePokemon) Evolve(newBase pokeapi.Pokemon, client pokeapi.Client) {
 		}
 	}
 }
+
+// Clone copies the pokemon so it can battle without changing the original
+func (p *BattlePokemon) Clone() *BattlePokemon {
+	clone := *p
+	clone.Moves = append([]Move(nil), p.Moves...)
+	return &clone
+}
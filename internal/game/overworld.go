@@ -0,0 +1,115 @@
+package game
+
+import "fmt"
+
+// Encounter methods that need an item or a field move
+const (
+	MethodSurf     = "surf"
+	MethodOldRod   = "old-rod"
+	MethodGoodRod  = "good-rod"
+	MethodSuperRod = "super-rod"
+)
+
+// Rods are the fishing rods, weakest first. Each one is also the encounter method it fishes with.
+var Rods = []string{MethodOldRod, MethodGoodRod, MethodSuperRod}
+
+// repelSteps is how many steps each kind of repel lasts
+var repelSteps = map[string]int{
+	"repel":       100,
+	"super-repel": 200,
+	"max-repel":   250,
+}
+
+// fieldMoveMethods are encounter methods a party member has to know a move for
+var fieldMoveMethods = map[string]string{
+	MethodSurf:   "surf",
+	"headbutt":   "headbutt",
+	"rock-smash": "rock-smash",
+}
+
+// IsRepel reports whether an item is a kind of repel
+func IsRepel(name string) bool {
+	_, ok := repelSteps[name]
+	return ok
+}
+
+// IsRod reports whether an item is a fishing rod
+func IsRod(name string) bool {
+	for _, rod := range Rods {
+		if rod == name {
+			return true
+		}
+	}
+	return false
+}
+
+// UseRepel starts a repel from the bag. Only one can be active at a time.
+func (inv *PlayerInventory) UseRepel(name string) error {
+	if !IsRepel(name) {
+		return fmt.Errorf("%s isn't a repel", name)
+	}
+	if inv.Repels[name] <= 0 {
+		return fmt.Errorf("you don't have any %s", name)
+	}
+	if inv.RepelSteps > 0 {
+		return fmt.Errorf("the last repel still has %d steps left", inv.RepelSteps)
+	}
+	inv.Repels[name]--
+	inv.RepelSteps = repelSteps[name]
+	return nil
+}
+
+// RepelStep takes one step off the active repel, reporting whether it just wore off
+func (inv *PlayerInventory) RepelStep() bool {
+	if inv.RepelSteps <= 0 {
+		return false
+	}
+	inv.RepelSteps--
+	return inv.RepelSteps == 0
+}
+
+// BestRod is the strongest rod owned, or "" without one
+func (inv *PlayerInventory) BestRod() string {
+	best := ""
+	for _, rod := range Rods {
+		if inv.Rods[rod] {
+			best = rod
+		}
+	}
+	return best
+}
+
+// RepelBlocks reports whether a repel keeps a wild pokemon of this level
+// away: only those at least as strong as the party's lead get through.
+func RepelBlocks(level int, party []*BattlePokemon) bool {
+	for _, p := range party {
+		if isHealthy(p) {
+			return level < p.Level
+		}
+	}
+	return false
+}
+
+// CanUseMethod checks the player has what an encounter method needs: a rod to fish
+// with, or a party member that knows the field move. Anyone can swim if they're water type.
+func CanUseMethod(method string, inv *PlayerInventory, party []*BattlePokemon) error {
+	if IsRod(method) {
+		if !inv.Rods[method] {
+			return fmt.Errorf("you need a %s to fish here", method)
+		}
+		return nil
+	}
+	move, ok := fieldMoveMethods[method]
+	if !ok {
+		return nil
+	}
+	for _, p := range party {
+		if p.Status == StatusFainted {
+			continue
+		}
+		if p.KnowsMove(move) || method == MethodSurf && p.HasType("water") {
+			return nil
+		}
+	}
+	return fmt.Errorf("none of your Pokemon can use %s", move)
+}
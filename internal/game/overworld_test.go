@@ -0,0 +1,126 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUseRepel(t *testing.T) {
+	inv := &PlayerInventory{Repels: map[string]int{"super-repel": 1}}
+
+	if err := inv.UseRepel("repel"); err == nil {
+		t.Errorf("expected an error using a repel the player doesn't have")
+	}
+	if err := inv.UseRepel("potion"); err == nil {
+		t.Errorf("expected an error using a potion as a repel")
+	}
+	if err := inv.UseRepel("super-repel"); err != nil {
+		t.Fatalf("UseRepel: %v", err)
+	}
+	if inv.RepelSteps != 200 || inv.Repels["super-repel"] != 0 {
+		t.Errorf("expected 200 steps and no super repels left, got %d and %d", inv.RepelSteps, inv.Repels["super-repel"])
+	}
+
+	inv.Repels["repel"] = 1
+	if err := inv.UseRepel("repel"); err == nil {
+		t.Errorf("expected an error stacking repels")
+	}
+
+	for i := 1; i < 200; i++ {
+		if inv.RepelStep() {
+			t.Fatalf("expected the repel to last 200 steps, wore off after %d", i)
+		}
+	}
+	if !inv.RepelStep() {
+		t.Errorf("expected the repel to wear off on step 200")
+	}
+	if inv.RepelStep() {
+		t.Errorf("expected no repel to wear off once it's gone")
+	}
+}
+
+func TestBestRod(t *testing.T) {
+	cases := []struct {
+		rods     map[string]bool
+		expected string
+	}{
+		{nil, ""},
+		{map[string]bool{"old-rod": true}, "old-rod"},
+		{map[string]bool{"old-rod": true, "super-rod": true}, "super-rod"},
+		{map[string]bool{"good-rod": true, "old-rod": true}, "good-rod"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			inv := &PlayerInventory{Rods: c.rods}
+			if got := inv.BestRod(); got != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestRepelBlocks(t *testing.T) {
+	fainted := newTestMon(t, "rattata", 30, "normal")
+	fainted.Status = StatusFainted
+	fainted.Stats.HP = 0
+	lead := newTestMon(t, "pidgey", 10, "normal", "flying")
+	party := []*BattlePokemon{fainted, lead}
+
+	cases := []struct {
+		level   int
+		blocked bool
+	}{
+		{5, true},
+		{9, true},
+		{10, false},
+		{25, false},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := RepelBlocks(c.level, party); got != c.blocked {
+				t.Errorf("expected blocked=%v for Lvl %d, got %v", c.blocked, c.level, got)
+			}
+		})
+	}
+}
+
+func TestCanUseMethod(t *testing.T) {
+	rattata := newTestMon(t, "rattata", 5, "normal")
+	magikarp := newTestMon(t, "magikarp", 5, "water")
+	surfer := newTestMon(t, "rattata", 5, "normal")
+	surfer.Moves = []Move{{Name: "surf", Type: "water"}}
+	faintedSurfer := newTestMon(t, "rattata", 5, "normal")
+	faintedSurfer.Moves = []Move{{Name: "surf", Type: "water"}}
+	faintedSurfer.Status = StatusFainted
+
+	withRod := &PlayerInventory{Rods: map[string]bool{"old-rod": true}}
+	cases := []struct {
+		method string
+		inv    *PlayerInventory
+		party  []*BattlePokemon
+		ok     bool
+	}{
+		{MethodWalk, &PlayerInventory{}, []*BattlePokemon{rattata}, true},
+		{MethodOldRod, withRod, []*BattlePokemon{rattata}, true},
+		{MethodGoodRod, withRod, []*BattlePokemon{rattata}, false},
+		{MethodSurf, &PlayerInventory{}, []*BattlePokemon{rattata}, false},
+		{MethodSurf, &PlayerInventory{}, []*BattlePokemon{rattata, magikarp}, true},
+		{MethodSurf, &PlayerInventory{}, []*BattlePokemon{surfer}, true},
+		{MethodSurf, &PlayerInventory{}, []*BattlePokemon{faintedSurfer}, false},
+		{"headbutt", &PlayerInventory{}, []*BattlePokemon{magikarp}, false},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			err := CanUseMethod(c.method, c.inv, c.party)
+			if c.ok && err != nil {
+				t.Errorf("expected to %s, got %v", c.method, err)
+			}
+			if !c.ok && err == nil {
+				t.Errorf("expected an error trying to %s", c.method)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package game
+
+import "time"
+
+// Replay is a recorded battle that can be saved and played back later
+type Replay struct {
+	Recorded time.Time `json:"recorded"`
+	Player   string    `json:"player"`   // The first pokemon the player sent out
+	Opponent string    `json:"opponent"` // The wild pokemon or the trainer
+	Outcome  string    `json:"outcome"`
+	Turns    int       `json:"turns"`
+	Seed     int64     `json:"seed"` // Replays the battle's random rolls
+	Events   []Event   `json:"events"`
+}
+
+// Replay records the battle so far
+func (b *Battle) Replay() Replay {
+	r := Replay{
+		Recorded: time.Now(),
+		Opponent: b.Foe().Nickname,
+		Outcome:  b.outcome.String(),
+		Turns:    b.Turn - 1,
+		Seed:     b.rng.Seed(),
+		Events:   b.log,
+	}
+	if b.Trainer != nil {
+		r.Opponent = b.Trainer.Title()
+	}
+	for _, e := range b.log {
+		if e.Kind == EventBattleStart {
+			r.Player = e.Actor
+			break
+		}
+	}
+	return r
+}
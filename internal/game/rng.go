@@ -0,0 +1,39 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RNG is the game's only source of randomness. Two sessions started
+// with the same seed roll the same levels, moves, hits and catches.
+type RNG struct {
+	r    *rand.Rand
+	seed int64
+}
+
+// NewRNG creates a generator that always produces the same sequence for a seed
+func NewRNG(seed int64) *RNG {
+	return &RNG{r: rand.New(rand.NewSource(seed)), seed: seed}
+}
+
+// NewRandomRNG seeds a generator from the clock
+func NewRandomRNG() *RNG {
+	return NewRNG(time.Now().UnixNano())
+}
+
+// Seed is the value the generator was created with
+func (g *RNG) Seed() int64 {
+	return g.seed
+}
+
+// Intn returns a number in [0, n)
+func (g *RNG) Intn(n int) int {
+	return g.r.Intn(n)
+}
+
+// Fork derives an independent generator, so a battle can be
+// replayed from its own seed no matter what happened before it
+func (g *RNG) Fork() *RNG {
+	return NewRNG(g.r.Int63())
+}
@@ -0,0 +1,88 @@
+package game
+
+// Side conditions, named after the moves that set them
+const (
+	ConditionStealthRock = "stealth-rock"
+	ConditionSpikes      = "spikes"
+	ConditionToxicSpikes = "toxic-spikes"
+	ConditionReflect     = "reflect"
+	ConditionLightScreen = "light-screen"
+)
+
+// ScreenTurns is how long Reflect and Light Screen last
+const ScreenTurns = 5
+
+// Screens halve damage in single battles and take a third off in doubles
+const (
+	ScreenMultiplier       = 0.5
+	DoubleScreenMultiplier = 2.0 / 3.0
+)
+
+// sideConditionMaxLayers is how many times each hazard stacks. Screens don't.
+var sideConditionMaxLayers = map[string]int{
+	ConditionStealthRock: 1,
+	ConditionSpikes:      3,
+	ConditionToxicSpikes: 2,
+	ConditionReflect:     1,
+	ConditionLightScreen: 1,
+}
+
+// specialTypes deal special damage for moves without a damage class, as before the physical/special split
+var specialTypes = map[string]bool{
+	"fire":     true,
+	"water":    true,
+	"grass":    true,
+	"electric": true,
+	"ice":      true,
+	"psychic":  true,
+	"dragon":   true,
+	"dark":     true,
+}
+
+// SideConditions are the hazards and screens on one side of the field.
+// Hazards count layers, screens count turns left.
+type SideConditions map[string]int
+
+// IsScreen reports whether a condition wears off after a few turns
+func IsScreen(condition string) bool {
+	return condition == ConditionReflect || condition == ConditionLightScreen
+}
+
+// IsSideCondition reports whether a move sets a side condition
+func IsSideCondition(move string) bool {
+	_, ok := sideConditionMaxLayers[move]
+	return ok
+}
+
+// IsSpecial reports whether a move hits Light Screen rather than Reflect
+func (m Move) IsSpecial() bool {
+	if m.DamageClass != "" {
+		return m.DamageClass == "special"
+	}
+	return specialTypes[m.Type]
+}
+
+// grounded pokemon are hurt by Spikes and Toxic Spikes
+func grounded(p *BattlePokemon) bool {
+	return !p.HasType("flying") && p.Ability != "levitate"
+}
+
+// HazardDamage is what hazards take off a pokemon switching in
+func HazardDamage(conditions SideConditions, p *BattlePokemon) int {
+	damage := 0
+	if conditions[ConditionStealthRock] > 0 {
+		effectiveness := GetTypeEffectiveness("rock", p.TypeNames())
+		damage += int(float64(p.Stats.MaxHP) * effectiveness / 8)
+	}
+	if grounded(p) {
+		switch conditions[ConditionSpikes] {
+		case 1:
+			damage += p.Stats.MaxHP / 8
+		case 2:
+			damage += p.Stats.MaxHP / 6
+		case 3:
+			damage += p.Stats.MaxHP / 4
+		}
+	}
+	return damage
+}
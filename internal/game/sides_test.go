@@ -0,0 +1,107 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHazardDamage(t *testing.T) {
+	cases := []struct {
+		conditions SideConditions
+		types      []string
+		ability    string
+		fraction   int // Expected damage as 1/fraction of max HP, 0 for none
+	}{
+		{SideConditions{ConditionStealthRock: 1}, []string{"normal"}, "", 8},
+		{SideConditions{ConditionStealthRock: 1}, []string{"fire", "flying"}, "", 2},
+		{SideConditions{ConditionSpikes: 1}, []string{"normal"}, "", 8},
+		{SideConditions{ConditionSpikes: 3}, []string{"normal"}, "", 4},
+		{SideConditions{ConditionSpikes: 3}, []string{"normal", "flying"}, "", 0},
+		{SideConditions{ConditionSpikes: 2}, []string{"ghost"}, "levitate", 0},
+		{SideConditions{ConditionReflect: 5}, []string{"normal"}, "", 0},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			p := newTestMon(t, "mon", 50, c.types...)
+			p.Ability = c.ability
+			want := 0
+			if c.fraction > 0 {
+				want = p.Stats.MaxHP / c.fraction
+			}
+			if got := HazardDamage(c.conditions, p); got != want {
+				t.Errorf("expected %d damage, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestSideConditionsInBattle(t *testing.T) {
+	player := newTestMon(t, "machamp", 50, "fighting")
+	player.Moves = []Move{
+		{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35, DamageClass: "physical"},
+		{Name: ConditionStealthRock, Type: "rock", CurrentPP: 20, MaxPP: 20, Target: "opponents-field"},
+		{Name: ConditionReflect, Type: "psychic", CurrentPP: 20, MaxPP: 20, Target: "users-field"},
+	}
+	first := newTestMon(t, "rattata", 20, "normal")
+	second := newTestMon(t, "charmander", 20, "fire")
+	for _, foe := range []*BattlePokemon{first, second} {
+		foe.Moves = []Move{{Name: "scratch", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35, DamageClass: "physical"}}
+	}
+
+	b, _, err := NewBattle(BattleConfig{
+		Party:       []*BattlePokemon{player},
+		Trainer:     &Trainer{ID: "joey", Name: "Joey", Class: "youngster"},
+		TrainerTeam: []*BattlePokemon{first, second},
+		OpponentAI:  RandomAI{},
+		RNG:         NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+
+	// Reflect halves the scratch that follows it
+	player.Stats.HP = player.Stats.MaxHP
+	events, err := b.Step(Action{Kind: ActionMove, Move: 2})
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	unscreened := CalculateDamage(first, player, &first.Moves[0], false, MaxDamageRoll)
+	for _, e := range events {
+		if e.Kind == EventDamage && e.Side == SidePlayer && e.Amount > unscreened/2 && !hasEvent(events, EventCrit) {
+			t.Errorf("expected reflect to halve damage to at most %d, got %d", unscreened/2, e.Amount)
+		}
+	}
+	if b.SideConditions(SidePlayer)[ConditionReflect] != ScreenTurns-1 {
+		t.Errorf("expected reflect to count down, got %v", b.SideConditions(SidePlayer))
+	}
+	if events, _ := b.Step(Action{Kind: ActionMove, Move: 2}); !hasEvent(events, EventFailed) {
+		t.Errorf("expected a second reflect to fail, got %v", events)
+	}
+
+	// Stealth Rock hurts the fire type the trainer sends out next
+	if _, err := b.Step(Action{Kind: ActionMove, Move: 1}); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if b.SideConditions(SideOpponent)[ConditionStealthRock] != 1 {
+		t.Fatalf("expected stealth rock on the opposing side, got %v", b.SideConditions(SideOpponent))
+	}
+	first.Stats.HP = 1
+	events, err = b.Step(Action{Kind: ActionMove})
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if !hasEvent(events, EventHazardDamage) || second.Stats.HP != second.Stats.MaxHP-second.Stats.MaxHP/4 {
+		t.Errorf("expected charmander to lose a quarter of its HP on entry, got %v", events)
+	}
+
+	for i := 0; i < ScreenTurns && b.SideConditions(SidePlayer)[ConditionReflect] > 0; i++ {
+		second.Stats.HP = second.Stats.MaxHP
+		if _, err := b.Step(Action{Kind: ActionMove, Move: 1}); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+	if b.SideConditions(SidePlayer)[ConditionReflect] != 0 {
+		t.Errorf("expected reflect to wear off")
+	}
+}
@@ -0,0 +1,196 @@
+package game
+
+import (
+	"errors"
+	"sort"
+)
+
+// DefaultMaxTurns stops simulated battles that neither side can finish, e.g. when both are out of PP
+const DefaultMaxTurns = 200
+
+// SimulationConfig describes a matchup to play over and over with AIs on both sides
+type SimulationConfig struct {
+	Team       []*BattlePokemon // The player side, copied fresh for every battle
+	Opponents  []*BattlePokemon // Fought like a trainer's team
+	TeamAI     AI               // nil for TypeAwareAI
+	OpponentAI AI               // nil for TypeAwareAI
+	Battles    int
+	MaxTurns   int  // 0 for DefaultMaxTurns
+	Double     bool // Two pokemon a side
+	RNG        *RNG
+}
+
+// SimulationResult sums up every simulated battle
+type SimulationResult struct {
+	Battles    int
+	Wins       int // Won by the team
+	Losses     int
+	Draws      int // Hit MaxTurns
+	TotalTurns int
+	Damage     []*DamageStats // One entry per side and move, in order of first use
+}
+
+// DamageStats is the damage distribution of one move
+type DamageStats struct {
+	Side    string
+	Move    string
+	Uses    int
+	Misses  int
+	Crits   int
+	Damages []int // Every hit that landed
+}
+
+// WinRate is the fraction of battles the team won
+func (r SimulationResult) WinRate() float64 {
+	if r.Battles == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Battles)
+}
+
+// AverageTurns is the mean battle length
+func (r SimulationResult) AverageTurns() float64 {
+	if r.Battles == 0 {
+		return 0
+	}
+	return float64(r.TotalTurns) / float64(r.Battles)
+}
+
+// Mean is the average damage per hit
+func (d *DamageStats) Mean() float64 {
+	if len(d.Damages) == 0 {
+		return 0
+	}
+	total := 0
+	for _, dmg := range d.Damages {
+		total += dmg
+	}
+	return float64(total) / float64(len(d.Damages))
+}
+
+// Percentile returns the damage at or below which p percent of hits fall
+func (d *DamageStats) Percentile(p float64) int {
+	if len(d.Damages) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), d.Damages...)
+	sort.Ints(sorted)
+	idx := int(p / 100 * float64(len(sorted)-1))
+	return sorted[max(0, min(idx, len(sorted)-1))]
+}
+
+// Simulate plays the matchup cfg.Battles times without any I/O
+func Simulate(cfg SimulationConfig) (SimulationResult, error) {
+	var result SimulationResult
+	if len(cfg.Team) == 0 || len(cfg.Opponents) == 0 {
+		return result, errors.New("a simulation needs a team and an opponent")
+	}
+	if cfg.TeamAI == nil {
+		cfg.TeamAI = TypeAwareAI{}
+	}
+	if cfg.OpponentAI == nil {
+		cfg.OpponentAI = TypeAwareAI{}
+	}
+	if cfg.MaxTurns <= 0 {
+		cfg.MaxTurns = DefaultMaxTurns
+	}
+	if cfg.RNG == nil {
+		cfg.RNG = NewRandomRNG()
+	}
+
+	damage := map[string]*DamageStats{}
+	for i := 0; i < cfg.Battles; i++ {
+		b, _, err := NewBattle(BattleConfig{
+			Party:       cloneTeam(cfg.Team),
+			Trainer:     &Trainer{Name: "Opponent"},
+			TrainerTeam: cloneTeam(cfg.Opponents),
+			Double:      cfg.Double,
+			OpponentAI:  cfg.OpponentAI,
+			RNG:         cfg.RNG.Fork(),
+		})
+		if err != nil {
+			return result, err
+		}
+
+		for !b.Over() && b.Turn <= cfg.MaxTurns {
+			if _, err := b.Step(aiActions(b, cfg.TeamAI)...); err != nil {
+				// The AI asked for something illegal, fall back to attacking
+				var fallback []Action
+				for _, p := range b.Actives() {
+					fallback = append(fallback, Action{Kind: ActionMove, Move: usableMoves(p)[0]})
+				}
+				if _, err := b.Step(fallback...); err != nil {
+					return result, err
+				}
+			}
+		}
+
+		result.Battles++
+		result.TotalTurns += b.Turn - 1
+		switch b.Outcome() {
+		case OutcomeWon:
+			result.Wins++
+		case OutcomeLost:
+			result.Losses++
+		default:
+			result.Draws++
+		}
+		result.Damage = collectDamage(b.Log(), damage, result.Damage)
+	}
+	return result, nil
+}
+
+func cloneTeam(team []*BattlePokemon) []*BattlePokemon {
+	clones := make([]*BattlePokemon, len(team))
+	for i, p := range team {
+		clones[i] = p.Clone()
+	}
+	return clones
+}
+
+// aiActions lets an AI play each of the player's pokemon in battle
+func aiActions(b *Battle, ai AI) []Action {
+	var actions []Action
+	switchingIn := make(map[int]bool)
+	potions := b.Inventory.Potions
+	for _, p := range b.Actives() {
+		actions = append(actions, b.aiAction(p, ai, switchingIn, &potions))
+	}
+	return actions
+}
+
+// collectDamage attributes each hit, miss and crit in a battle log to the move that caused it
+func collectDamage(log []Event, byMove map[string]*DamageStats, order []*DamageStats) []*DamageStats {
+	var current *DamageStats
+	for _, e := range log {
+		switch e.Kind {
+		case EventMove:
+			key := e.Side + "/" + e.Move
+			current = byMove[key]
+			if current == nil {
+				current = &DamageStats{Side: e.Side, Move: e.Move}
+				byMove[key] = current
+				order = append(order, current)
+			}
+			current.Uses++
+		case EventMiss:
+			if current != nil {
+				current.Misses++
+			}
+		case EventCrit:
+			if current != nil {
+				current.Crits++
+			}
+		case EventDamage:
+			if current != nil {
+				current.Damages = append(current.Damages, e.Amount)
+			}
+		default:
+			// Anything else (switches, berries, leftovers) ends the move
+			if e.Kind != EventEffectiveness {
+				current = nil
+			}
+		}
+	}
+	return order
+}
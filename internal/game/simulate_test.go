@@ -0,0 +1,58 @@
+package game
+
+import "testing"
+
+func TestSimulate(t *testing.T) {
+	strong := newTestMon(t, "machamp", 40, "fighting")
+	strong.Moves = []Move{{Name: "karate-chop", Type: "fighting", Power: 50, Accuracy: 100, CurrentPP: 25, MaxPP: 25}}
+	weak := newTestMon(t, "rattata", 10, "normal")
+	weak.Moves = []Move{{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, CurrentPP: 35, MaxPP: 35}}
+
+	result, err := Simulate(SimulationConfig{
+		Team:      []*BattlePokemon{strong},
+		Opponents: []*BattlePokemon{weak},
+		Battles:   200,
+		RNG:       NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+
+	if result.Battles != 200 || result.Wins+result.Losses+result.Draws != 200 {
+		t.Fatalf("battles don't add up: %+v", result)
+	}
+	if result.WinRate() < 0.99 {
+		t.Errorf("expected the level 40 to win nearly every time, got %.2f", result.WinRate())
+	}
+	if result.AverageTurns() < 1 {
+		t.Errorf("expected at least one turn per battle, got %.2f", result.AverageTurns())
+	}
+	if strong.Stats.HP != strong.Stats.MaxHP || strong.Moves[0].CurrentPP != 25 {
+		t.Errorf("simulation changed the original pokemon")
+	}
+
+	if len(result.Damage) == 0 || result.Damage[0].Side != SidePlayer || result.Damage[0].Move != "karate-chop" {
+		t.Fatalf("expected damage stats for karate-chop first, got %+v", result.Damage)
+	}
+	chop := result.Damage[0]
+	if chop.Uses < 200 || len(chop.Damages) == 0 {
+		t.Errorf("expected every battle to use karate-chop, got %d uses", chop.Uses)
+	}
+	if chop.Percentile(0) > chop.Percentile(50) || chop.Percentile(50) > chop.Percentile(100) {
+		t.Errorf("percentiles out of order")
+	}
+
+	doubles, err := Simulate(SimulationConfig{
+		Team:      []*BattlePokemon{strong, strong.Clone()},
+		Opponents: []*BattlePokemon{weak, weak.Clone(), weak.Clone()},
+		Battles:   50,
+		Double:    true,
+		RNG:       NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("Simulate doubles: %v", err)
+	}
+	if doubles.WinRate() < 0.99 {
+		t.Errorf("expected two level 40s to win nearly every double battle, got %.2f", doubles.WinRate())
+	}
+}
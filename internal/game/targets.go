@@ -0,0 +1,43 @@
+package game
+
+// Move targets from the PokeAPI that the battle engine handles specially.
+// Anything aimed only at the user's own side deals no damage.
+const (
+	TargetSelected       = "selected-pokemon"
+	TargetRandomOpponent = "random-opponent"
+	TargetAllOpponents   = "all-opponents"
+	TargetAllOthers      = "all-other-pokemon" // Opponents and the ally, like Earthquake
+	TargetAllPokemon     = "all-pokemon"
+)
+
+// SpreadMultiplier weakens a spread move when it hits more than one pokemon
+const SpreadMultiplier = 0.75
+
+// IsSpread reports whether the move hits several pokemon at once
+func (m Move) IsSpread() bool {
+	switch m.Target {
+	case TargetAllOpponents, TargetAllOthers, TargetAllPokemon:
+		return true
+	}
+	return false
+}
+
+// HitsAlly reports whether a spread move also hits the user's partner
+func (m Move) HitsAlly() bool {
+	return m.Target == TargetAllOthers || m.Target == TargetAllPokemon
+}
+
+// NeedsTarget reports whether the player picks which foe the move is aimed at
+func (m Move) NeedsTarget() bool {
+	switch m.Target {
+	case "", TargetSelected, "selected-pokemon-me-first", "specific-move":
+		return true
+	}
+	return false
+}
+
+// HitsOpponents reports whether the move can damage the other side at all.
+// Moves from old saves have no target and count as single-target.
+func (m Move) HitsOpponents() bool {
+	return m.NeedsTarget() || m.IsSpread() || m.Target == TargetRandomOpponent
+}
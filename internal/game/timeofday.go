@@ -0,0 +1,74 @@
+package game
+
+import "time"
+
+// TimeOfDay buckets a clock time into the periods PokeAPI uses
+func TimeOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 4 && h < 10:
+		return "morning"
+	case h >= 10 && h < 17:
+		return "day"
+	case h == 17:
+		return "dusk"
+	default:
+		return "night"
+	}
+}
+
+// MatchesTimeOfDay checks a required period (e.g. an evolution's "day") against the current one
+func MatchesTimeOfDay(required, current string) bool {
+	switch required {
+	case "":
+		return true
+	case "day":
+		return current == "morning" || current == "day"
+	default:
+		return required == current
+	}
+}
+
+// Clock is the in-game time. From Start it runs Speed times faster than the
+// real clock, beginning at Game. A zero Clock just follows the real clock.
+type Clock struct {
+	Speed float64   // Game minutes per real minute
+	Start time.Time // Real time the clock was set
+	Game  time.Time // Game time at Start
+}
+
+// NewClock starts a game clock at game, running speed times faster than real time.
+// A speed of 0 follows the real clock.
+func NewClock(speed float64, game, now time.Time) Clock {
+	if speed <= 0 {
+		return Clock{}
+	}
+	return Clock{Speed: speed, Start: now, Game: game}
+}
+
+// StoppedClock always reads game, so time of day can't change a reproducible session
+func StoppedClock(game time.Time) Clock {
+	return Clock{Start: game, Game: game}
+}
+
+// Stopped reports whether the clock never moves
+func (c Clock) Stopped() bool {
+	return !c.Start.IsZero() && c.Speed == 0
+}
+
+// At is the game time when the real clock reads now
+func (c Clock) At(now time.Time) time.Time {
+	if c.Start.IsZero() {
+		return now
+	}
+	return c.Game.Add(time.Duration(float64(now.Sub(c.Start)) * c.Speed))
+}
+
+// TimeOfDay is the period of the game time when the real clock reads now
+func (c Clock) TimeOfDay(now time.Time) string {
+	return TimeOfDay(c.At(now))
+}
+
+// IsDark reports whether a period counts as night for things like the Dusk Ball
+func IsDark(timeOfDay string) bool {
+	return timeOfDay == "night" || timeOfDay == "dusk"
+}
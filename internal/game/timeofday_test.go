@@ -0,0 +1,37 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	morning := time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		clock   Clock
+		elapsed time.Duration
+		period  string
+	}{
+		{Clock{}, 0, "day"}, // Follows the real clock
+		{Clock{}, 10 * time.Hour, "night"},
+		{NewClock(0, morning, start), 0, "day"},
+		{NewClock(1, morning, start), 0, "morning"},
+		{NewClock(1, morning, start), 5 * time.Hour, "day"},
+		{NewClock(60, morning, start), 11 * time.Minute, "dusk"},
+		{NewClock(60, morning, start), 15 * time.Minute, "night"},
+		{NewClock(60, morning, start), 24 * time.Minute, "morning"}, // The next day
+		{StoppedClock(morning), 0, "morning"},
+		{StoppedClock(morning), 12 * time.Hour, "morning"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := c.clock.TimeOfDay(start.Add(c.elapsed)); got != c.period {
+				t.Errorf("expected %s, got %s at %v", c.period, got, c.clock.At(start.Add(c.elapsed)))
+			}
+		})
+	}
+}
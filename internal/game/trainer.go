@@ -0,0 +1,182 @@
+package game
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+//go:embed data/trainers.json
+var trainerData []byte
+
+// Trainer is an opponent with a party, defined in data/trainers.json
+type Trainer struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Class   string           `json:"class"`
+	AI      string           `json:"ai,omitempty"` // Defaults to "trainer"
+	Potions int              `json:"potions,omitempty"`
+	Double  bool             `json:"double,omitempty"` // Fights two pokemon at a time
+	Team    []TrainerPokemon `json:"team"`
+}
+
+// TrainerPokemon is one member of a trainer's team
+type TrainerPokemon struct {
+	Species  string   `json:"species"`
+	Level    int      `json:"level"`
+	Moves    []string `json:"moves,omitempty"` // A random move when empty
+	HeldItem string   `json:"held_item,omitempty"`
+}
+
+// trainerClassPayouts is the prize money per level of the trainer's last pokemon
+var trainerClassPayouts = map[string]int{
+	"youngster":   16,
+	"lass":        16,
+	"bug-catcher": 16,
+	"camper":      20,
+	"picnicker":   20,
+	"twins":       20,
+	"swimmer":     8,
+	"sailor":      32,
+	"hiker":       36,
+	"fisherman":   40,
+	"rival":       35,
+	"ace-trainer": 60,
+	"beauty":      70,
+	"gym-leader":  100,
+	"elite-four":  100,
+	"champion":    200,
+}
+
+// DefaultTrainerPayout is used for classes missing from the payout table
+const DefaultTrainerPayout = 20
+
+// LoadTrainers returns the trainers bundled with the game
+func LoadTrainers() ([]Trainer, error) {
+	return ParseTrainers(trainerData)
+}
+
+// ParseTrainers decodes and checks a trainer data file
+func ParseTrainers(data []byte) ([]Trainer, error) {
+	var trainers []Trainer
+	if err := json.Unmarshal(data, &trainers); err != nil {
+		return nil, fmt.Errorf("invalid trainer data: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range trainers {
+		if seen[t.ID] {
+			return nil, fmt.Errorf("trainer '%s' needs a unique id", t.ID)
+		}
+		seen[t.ID] = true
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return trainers, nil
+}
+
+func (t Trainer) validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("trainer %s needs an id", t.Name)
+	}
+	if len(t.Team) == 0 || len(t.Team) > 6 {
+		return fmt.Errorf("trainer %s must have 1 to 6 pokemon", t.ID)
+	}
+	if t.Double && len(t.Team) < 2 {
+		return fmt.Errorf("trainer %s needs at least 2 pokemon for double battles", t.ID)
+	}
+	for _, p := range t.Team {
+		if p.Species == "" || p.Level < 1 || p.Level > MaxLevel {
+			return fmt.Errorf("trainer %s has an invalid pokemon: %+v", t.ID, p)
+		}
+	}
+	if t.AI != "" {
+		if _, err := NewAI(t.AI); err != nil {
+			return fmt.Errorf("trainer %s: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// FindTrainer looks a trainer up by id
+func FindTrainer(trainers []Trainer, id string) (Trainer, bool) {
+	for _, t := range trainers {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return Trainer{}, false
+}
+
+// Title is how the trainer introduces themselves, e.g. "Youngster Joey"
+func (t Trainer) Title() string {
+	if t.Class == "" {
+		return t.Name
+	}
+	return capitalizeWords(t.Class) + " " + t.Name
+}
+
+// capitalizeWords turns a dashed name like "ace-trainer" into "Ace Trainer"
+func capitalizeWords(name string) string {
+	words := strings.Split(name, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// OpponentAI is the strategy the trainer battles with
+func (t Trainer) OpponentAI() AI {
+	if ai, err := NewAI(t.AI); err == nil {
+		return ai
+	}
+	return TrainerAI{}
+}
+
+// PrizeMoney is what the trainer pays out when beaten
+func (t Trainer) PrizeMoney() int {
+	payout, ok := trainerClassPayouts[t.Class]
+	if !ok {
+		payout = DefaultTrainerPayout
+	}
+	if len(t.Team) == 0 {
+		return payout
+	}
+	return payout * t.Team[len(t.Team)-1].Level
+}
+
+// BuildTeam creates the trainer's pokemon from the API
+func (t Trainer) BuildTeam(client pokeapi.Client, rng *RNG) ([]*BattlePokemon, error) {
+	team := make([]*BattlePokemon, 0, len(t.Team))
+	for _, member := range t.Team {
+		base, err := client.GetPokemon(member.Species)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't fetch %s's %s: %w", t.Name, member.Species, err)
+		}
+		p, err := NewBattlePokemon(base, member.Level, client, rng)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(member.Moves) > 0 {
+			var moves []Move
+			for _, name := range member.Moves {
+				apiMove, err := client.GetMove(name)
+				if err != nil {
+					return nil, fmt.Errorf("couldn't fetch move %s: %w", name, err)
+				}
+				moves = append(moves, NewMove(apiMove))
+			}
+			p.Moves = moves
+		}
+		p.HeldItem = member.HeldItem
+		team = append(team, p)
+	}
+	return team, nil
+}
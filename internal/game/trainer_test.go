@@ -0,0 +1,42 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLoadTrainers(t *testing.T) {
+	trainers, err := LoadTrainers()
+	if err != nil {
+		t.Fatalf("bundled trainer data is invalid: %v", err)
+	}
+	joey, ok := FindTrainer(trainers, "joey")
+	if !ok {
+		t.Fatalf("expected youngster joey in the bundled trainers")
+	}
+	if joey.Title() != "Youngster Joey" {
+		t.Errorf("unexpected title %q", joey.Title())
+	}
+	if joey.PrizeMoney() != 16*4 {
+		t.Errorf("expected ₽64 from a youngster with a level 4, got ₽%d", joey.PrizeMoney())
+	}
+}
+
+func TestParseTrainersRejectsBadData(t *testing.T) {
+	cases := []string{
+		`not json`,
+		`[{"id": "", "team": [{"species": "rattata", "level": 5}]}]`,
+		`[{"id": "a", "team": [{"species": "rattata", "level": 5}]}, {"id": "a", "team": [{"species": "pidgey", "level": 5}]}]`,
+		`[{"id": "a", "team": []}]`,
+		`[{"id": "a", "team": [{"species": "rattata", "level": 0}]}]`,
+		`[{"id": "a", "ai": "genius", "team": [{"species": "rattata", "level": 5}]}]`,
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if _, err := ParseTrainers([]byte(c)); err == nil {
+				t.Errorf("expected an error for %s", c)
+			}
+		})
+	}
+}
@@ -17,6 +17,10 @@ var typeChart = map[string]map[string]float64{
 		"water": 2.0, "flying": 2.0,
 		"ground": 0.5, "grass": 0.5, "electric": 0.5,
 	},
+	"rock": {
+		"fire": 2.0, "ice": 2.0, "flying": 2.0, "bug": 2.0,
+		"fighting": 0.5, "ground": 0.5, "steel": 0.5,
+	},
 	"normal": {
 		"ghost": 0.5, "rock": 0.5,
 	},
@@ -0,0 +1,80 @@
+package game
+
+// Weather is battle-wide and affects every pokemon on the field
+type Weather string
+
+const (
+	WeatherNone      Weather = ""
+	WeatherRain      Weather = "rain"
+	WeatherSun       Weather = "sun"
+	WeatherSandstorm Weather = "sandstorm"
+	WeatherHail      Weather = "hail"
+)
+
+// DefaultWeatherTurns is how long weather from a move or ability lasts
+const DefaultWeatherTurns = 5
+
+// weatherMoves set the weather when used
+var weatherMoves = map[string]Weather{
+	"rain-dance": WeatherRain,
+	"sunny-day":  WeatherSun,
+	"sandstorm":  WeatherSandstorm,
+	"hail":       WeatherHail,
+}
+
+// weatherAbilities set the weather when their pokemon is sent out
+var weatherAbilities = map[string]Weather{
+	"drizzle":      WeatherRain,
+	"drought":      WeatherSun,
+	"sand-stream":  WeatherSandstorm,
+	"snow-warning": WeatherHail,
+}
+
+// weatherImmuneTypes don't take chip damage from the weather
+var weatherImmuneTypes = map[Weather][]string{
+	WeatherSandstorm: {"rock", "ground", "steel"},
+	WeatherHail:      {"ice"},
+}
+
+// WeatherMultiplier is how much the weather boosts or weakens a move type
+func WeatherMultiplier(w Weather, moveType string) float64 {
+	switch {
+	case w == WeatherRain && moveType == "water", w == WeatherSun && moveType == "fire":
+		return 1.5
+	case w == WeatherRain && moveType == "fire", w == WeatherSun && moveType == "water":
+		return 0.5
+	}
+	return 1.0
+}
+
+// WeatherAccuracy adjusts a move's accuracy. 0 means the move can't miss.
+func WeatherAccuracy(w Weather, move *Move) int {
+	switch move.Name {
+	case "thunder", "hurricane":
+		if w == WeatherRain {
+			return 0
+		}
+		if w == WeatherSun {
+			return 50
+		}
+	case "blizzard":
+		if w == WeatherHail {
+			return 0
+		}
+	}
+	return move.Accuracy
+}
+
+// WeatherChipDamage is what the weather takes off a pokemon at the end of each turn
+func WeatherChipDamage(w Weather, p *BattlePokemon) int {
+	immune, ok := weatherImmuneTypes[w]
+	if !ok {
+		return 0
+	}
+	for _, t := range immune {
+		if p.HasType(t) {
+			return 0
+		}
+	}
+	return max(p.Stats.MaxHP/16, 1)
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWeatherModifiers(t *testing.T) {
+	thunder := &Move{Name: "thunder", Type: "electric", Power: 110, Accuracy: 70}
+	blizzard := &Move{Name: "blizzard", Type: "ice", Power: 110, Accuracy: 70}
+	cases := []struct {
+		weather    Weather
+		moveType   string
+		multiplier float64
+		move       *Move
+		accuracy   int
+	}{
+		{WeatherRain, "water", 1.5, thunder, 0},
+		{WeatherRain, "fire", 0.5, blizzard, 70},
+		{WeatherSun, "fire", 1.5, thunder, 50},
+		{WeatherSun, "water", 0.5, thunder, 50},
+		{WeatherHail, "water", 1.0, blizzard, 0},
+		{WeatherNone, "fire", 1.0, thunder, 70},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := WeatherMultiplier(c.weather, c.moveType); got != c.multiplier {
+				t.Errorf("expected %s moves at x%.1f in %q, got x%.1f", c.moveType, c.multiplier, c.weather, got)
+			}
+			if got := WeatherAccuracy(c.weather, c.move); got != c.accuracy {
+				t.Errorf("expected %s accuracy %d in %q, got %d", c.move.Name, c.accuracy, c.weather, got)
+			}
+		})
+	}
+}
+
+func TestWeatherChipDamage(t *testing.T) {
+	cases := []struct {
+		weather Weather
+		types   []string
+		hurt    bool
+	}{
+		{WeatherSandstorm, []string{"normal"}, true},
+		{WeatherSandstorm, []string{"rock", "ground"}, false},
+		{WeatherSandstorm, []string{"water", "steel"}, false},
+		{WeatherHail, []string{"grass"}, true},
+		{WeatherHail, []string{"ice"}, false},
+		{WeatherRain, []string{"fire"}, false},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			p := newTestMon(t, "mon", 20, c.types...)
+			chip := WeatherChipDamage(c.weather, p)
+			if c.hurt && chip != p.Stats.MaxHP/16 {
+				t.Errorf("expected 1/16 of max HP, got %d", chip)
+			}
+			if !c.hurt && chip != 0 {
+				t.Errorf("expected no damage, got %d", chip)
+			}
+		})
+	}
+}
+
+func TestBattleWeather(t *testing.T) {
+	poliwag := newTestMon(t, "poliwag", 30, "water")
+	poliwag.Ability = "drizzle"
+	poliwag.Moves = []Move{
+		{Name: "water-gun", Type: "water", Power: 40, Accuracy: 100, CurrentPP: 25, MaxPP: 25},
+		{Name: "sandstorm", Type: "rock", Accuracy: 0, CurrentPP: 10, MaxPP: 10, Target: "entire-field"},
+	}
+	foe := newTestMon(t, "snorlax", 100, "normal")
+	foe.Moves = []Move{{Name: "harden", Type: "normal", Accuracy: 100, CurrentPP: 30, MaxPP: 30, Target: "user"}}
+
+	b, events, err := NewBattle(BattleConfig{Party: []*BattlePokemon{poliwag}, Wild: foe, RNG: NewRNG(1)})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+	if last := events[len(events)-1]; last.Kind != EventWeatherStart || last.Weather != WeatherRain || last.Ability != "drizzle" {
+		t.Fatalf("expected drizzle to start the rain, got %v", events)
+	}
+
+	// Rain boosts water moves by half
+	events, err = b.Step(Action{Kind: ActionMove})
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	maxDamage := CalculateDamage(poliwag, foe, &poliwag.Moves[0], true, MaxDamageRoll)
+	minDamage := CalculateDamage(poliwag, foe, &poliwag.Moves[0], false, MinDamageRoll)
+	for _, e := range events {
+		if e.Kind == EventDamage && (e.Amount < minDamage*3/2 || e.Amount > maxDamage*3/2) {
+			t.Errorf("expected rain-boosted damage between %d and %d, got %d", minDamage*3/2, maxDamage*3/2, e.Amount)
+		}
+	}
+
+	// A move replaces the weather and the sandstorm chips at the foe, but not twice in a row
+	events, err = b.Step(Action{Kind: ActionMove, Move: 1})
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if b.Weather != WeatherSandstorm || b.WeatherTurnsLeft() != DefaultWeatherTurns-1 {
+		t.Fatalf("expected a sandstorm with %d turns left, got %q with %d", DefaultWeatherTurns-1, b.Weather, b.WeatherTurnsLeft())
+	}
+	chipped := 0
+	for _, e := range events {
+		if e.Kind == EventWeatherDamage {
+			chipped++
+		}
+	}
+	if hasEvent(events, EventFailed) || chipped != 2 {
+		t.Errorf("expected the sandstorm to hit both pokemon, got %v", events)
+	}
+	events, _ = b.Step(Action{Kind: ActionMove, Move: 1})
+	if !hasEvent(events, EventFailed) {
+		t.Errorf("expected a second sandstorm to fail, got %v", events)
+	}
+
+	for i := 0; i < DefaultWeatherTurns && b.Weather != WeatherNone; i++ {
+		poliwag.Stats.HP = poliwag.Stats.MaxHP
+		if events, err = b.Step(Action{Kind: ActionMove}); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+	if b.Weather != WeatherNone {
+		t.Errorf("expected the sandstorm to die down, got %q", b.Weather)
+	}
+}
+
+func hasEvent(events []Event, kind EventKind) bool {
+	for _, e := range events {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,12 @@ type Client struct {
 	httpClient http.Client
 }
 
+// NamedAPIResource -
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
 // LocationArea -
 type LocationArea struct {
 	Name string `json:"name"`
@@ -29,12 +35,58 @@ type LocationAreaResponse struct {
 	Results  []LocationArea `json:"results"`
 }
 
+// NamedAPIResourceList is an unpaged list endpoint, like /region
+type NamedAPIResourceList struct {
+	Count   int                `json:"count"`
+	Results []NamedAPIResource `json:"results"`
+}
+
+// Region is a part of the world with its own locations, e.g. kanto
+type Region struct {
+	Name      string             `json:"name"`
+	Locations []NamedAPIResource `json:"locations"`
+}
+
+// Location is a place in a region, split into one or more areas
+type Location struct {
+	Name   string             `json:"name"`
+	Region NamedAPIResource   `json:"region"`
+	Areas  []NamedAPIResource `json:"areas"`
+}
+
 type LocationAreaDetail struct {
-	PokemonEncounters []struct {
-		Pokemon struct {
-			Name string `json:"name"`
-		} `json:"pokemon"`
-	} `json:"pokemon_encounters"`
+	Name                 string                `json:"name"`
+	Location             NamedAPIResource      `json:"location"`
+	EncounterMethodRates []EncounterMethodRate `json:"encounter_method_rates"`
+	PokemonEncounters    []PokemonEncounter    `json:"pokemon_encounters"`
+}
+
+// EncounterMethodRate is how often each version triggers encounters with a method, e.g. walking in tall grass
+type EncounterMethodRate struct {
+	EncounterMethod NamedAPIResource `json:"encounter_method"`
+	VersionDetails  []struct {
+		Rate    int              `json:"rate"`
+		Version NamedAPIResource `json:"version"`
+	} `json:"version_details"`
+}
+
+// PokemonEncounter is one species found in an area, per game version
+type PokemonEncounter struct {
+	Pokemon        NamedAPIResource `json:"pokemon"`
+	VersionDetails []struct {
+		Version          NamedAPIResource  `json:"version"`
+		MaxChance        int               `json:"max_chance"`
+		EncounterDetails []EncounterDetail `json:"encounter_details"`
+	} `json:"version_details"`
+}
+
+// EncounterDetail is one encounter slot: how, how likely and at what levels
+type EncounterDetail struct {
+	MinLevel        int                `json:"min_level"`
+	MaxLevel        int                `json:"max_level"`
+	Chance          int                `json:"chance"`
+	Method          NamedAPIResource   `json:"method"`
+	ConditionValues []NamedAPIResource `json:"condition_values"`
 }
 
 type Pokemon struct {
@@ -59,6 +111,11 @@ type Pokemon struct {
 			URL  string `json:"url"`
 		} `json:"move"`
 	} `json:"moves"`
+	Abilities []struct {
+		Ability  NamedAPIResource `json:"ability"`
+		IsHidden bool             `json:"is_hidden"`
+		Slot     int              `json:"slot"`
+	} `json:"abilities"`
 }
 
 type Move struct {
@@ -66,15 +123,35 @@ type Move struct {
 	Accuracy int    `json:"accuracy"`
 	Power    int    `json:"power"`
 	PP       int    `json:"pp"`
+	Priority int    `json:"priority"`
 	Type     struct {
 		Name string `json:"name"`
 	} `json:"type"`
+	Target      NamedAPIResource `json:"target"`
+	DamageClass NamedAPIResource `json:"damage_class"`
 }
 
 type PokemonSpecies struct {
 	EvolutionChain struct {
 		URL string `json:"url"`
 	} `json:"evolution_chain"`
+	GrowthRate struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"growth_rate"`
+	GenderRate    int `json:"gender_rate"` // Chance of being female in eighths, -1 for genderless
+	BaseHappiness int `json:"base_happiness"`
+	CaptureRate   int `json:"capture_rate"`
+}
+
+// GrowthRate -
+type GrowthRate struct {
+	Name    string `json:"name"`
+	Formula string `json:"formula"`
+	Levels  []struct {
+		Level      int `json:"level"`
+		Experience int `json:"experience"`
+	} `json:"levels"`
 }
 
 type EvolutionChainResponse struct {
@@ -90,13 +167,24 @@ type ChainLink struct {
 }
 
 type EvolutionDetail struct {
-	MinLevel *int `json:"min_level"`
-	Item     *struct {
-		Name string `json:"name"`
-	} `json:"item"`
-	Trigger struct {
-		Name string `json:"name"`
-	} `json:"trigger"`
+	Trigger               NamedAPIResource  `json:"trigger"`
+	MinLevel              *int              `json:"min_level"`
+	Item                  *NamedAPIResource `json:"item"`
+	HeldItem              *NamedAPIResource `json:"held_item"`
+	KnownMove             *NamedAPIResource `json:"known_move"`
+	KnownMoveType         *NamedAPIResource `json:"known_move_type"`
+	Location              *NamedAPIResource `json:"location"`
+	PartySpecies          *NamedAPIResource `json:"party_species"`
+	PartyType             *NamedAPIResource `json:"party_type"`
+	TradeSpecies          *NamedAPIResource `json:"trade_species"`
+	Gender                *int              `json:"gender"` // 1 female, 2 male
+	MinHappiness          *int              `json:"min_happiness"`
+	MinAffection          *int              `json:"min_affection"`
+	MinBeauty             *int              `json:"min_beauty"`
+	RelativePhysicalStats *int              `json:"relative_physical_stats"` // 1 Atk > Def, 0 equal, -1 Atk < Def
+	TimeOfDay             string            `json:"time_of_day"`
+	NeedsOverworldRain    bool              `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool              `json:"turn_upside_down"`
 }
 
 // NewClient -
@@ -351,3 +439,159 @@ func (c *Client) GetMove(name string) (Move, error) {
 	c.cache.Add(url, dat)
 	return moveResp, nil
 }
+
+// GetGrowthRate -
+func (c *Client) GetGrowthRate(name string) (GrowthRate, error) {
+	url := "https://pokeapi.co/api/v2/growth-rate/" + name
+
+	if val, ok := c.cache.Get(url); ok {
+		rateResp := GrowthRate{}
+		err := json.Unmarshal(val, &rateResp)
+		if err != nil {
+			return GrowthRate{}, err
+		}
+		return rateResp, nil
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return GrowthRate{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return GrowthRate{}, err
+	}
+	defer resp.Body.Close()
+
+	dat, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GrowthRate{}, err
+	}
+
+	rateResp := GrowthRate{}
+	err = json.Unmarshal(dat, &rateResp)
+	if err != nil {
+		return GrowthRate{}, err
+	}
+
+	c.cache.Add(url, dat)
+	return rateResp, nil
+}
+
+// GetRegions lists every region, e.g. kanto and johto
+func (c *Client) GetRegions() (NamedAPIResourceList, error) {
+	url := "https://pokeapi.co/api/v2/region"
+
+	if val, ok := c.cache.Get(url); ok {
+		regionsResp := NamedAPIResourceList{}
+		err := json.Unmarshal(val, &regionsResp)
+		if err != nil {
+			return NamedAPIResourceList{}, err
+		}
+		return regionsResp, nil
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return NamedAPIResourceList{}, err
+	}
+	defer resp.Body.Close()
+
+	dat, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	regionsResp := NamedAPIResourceList{}
+	err = json.Unmarshal(dat, &regionsResp)
+	if err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	c.cache.Add(url, dat)
+	return regionsResp, nil
+}
+
+// GetRegion -
+func (c *Client) GetRegion(name string) (Region, error) {
+	url := "https://pokeapi.co/api/v2/region/" + name
+
+	if val, ok := c.cache.Get(url); ok {
+		regionResp := Region{}
+		err := json.Unmarshal(val, &regionResp)
+		if err != nil {
+			return Region{}, err
+		}
+		return regionResp, nil
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return Region{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Region{}, err
+	}
+	defer resp.Body.Close()
+
+	dat, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Region{}, err
+	}
+
+	regionResp := Region{}
+	err = json.Unmarshal(dat, &regionResp)
+	if err != nil {
+		return Region{}, err
+	}
+
+	c.cache.Add(url, dat)
+	return regionResp, nil
+}
+
+// GetLocation -
+func (c *Client) GetLocation(name string) (Location, error) {
+	url := "https://pokeapi.co/api/v2/location/" + name
+
+	if val, ok := c.cache.Get(url); ok {
+		locationResp := Location{}
+		err := json.Unmarshal(val, &locationResp)
+		if err != nil {
+			return Location{}, err
+		}
+		return locationResp, nil
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return Location{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Location{}, err
+	}
+	defer resp.Body.Close()
+
+	dat, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Location{}, err
+	}
+
+	locationResp := Location{}
+	err = json.Unmarshal(dat, &locationResp)
+	if err != nil {
+		return Location{}, err
+	}
+
+	c.cache.Add(url, dat)
+	return locationResp, nil
+}
@@ -0,0 +1,316 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+)
+
+// BattleUI drives a game.Battle from the terminal
+type BattleUI struct {
+	In         Prompter
+	Out        io.Writer
+	ShakeDelay time.Duration // Pause before each ball shake for suspense
+}
+
+// errBack means the player backed out of a menu without choosing
+var errBack = fmt.Errorf("back")
+
+// Run plays the battle to the end. If input runs out the player tries to flee,
+// or forfeits a trainer battle since there's no running from one.
+func (ui BattleUI) Run(b *game.Battle) game.BattleOutcome {
+	for !b.Over() {
+		// --- 0. Replace anyone who fainted last turn ---
+		if pending := b.NeedsReplacement(); len(pending) > 0 {
+			ui.replaceMenu(b, pending[0])
+			continue
+		}
+
+		// --- 1. HUD ---
+		fmt.Fprintln(ui.Out)
+		for _, active := range b.Actives() {
+			fmt.Fprintf(ui.Out, "%s (Lvl %d): %d/%d HP\n", active.Nickname, active.Level, active.Stats.HP, active.Stats.MaxHP)
+		}
+		for _, foe := range b.Foes() {
+			fmt.Fprintf(ui.Out, "%s (Lvl %d): %d/%d HP\n", b.Name(foe), foe.Level, foe.Stats.HP, foe.Stats.MaxHP)
+		}
+		if b.Trainer != nil {
+			fmt.Fprintf(ui.Out, "%s has %d/%d Pokemon left\n", b.Trainer.Title(), b.OpponentsLeft(), len(b.Opponents))
+		}
+		if label := SideConditionsLabel(b.SideConditions(game.SideOpponent)); label != "" {
+			fmt.Fprintf(ui.Out, "Opposing side: %s\n", label)
+		}
+		if label := SideConditionsLabel(b.SideConditions(game.SidePlayer)); label != "" {
+			fmt.Fprintf(ui.Out, "Your side: %s\n", label)
+		}
+		if b.Weather != game.WeatherNone {
+			if turns := b.WeatherTurnsLeft(); turns > 0 {
+				fmt.Fprintf(ui.Out, "Weather: %s (%d turns left)\n", WeatherLabel(b.Weather), turns)
+			} else {
+				fmt.Fprintf(ui.Out, "Weather: %s\n", WeatherLabel(b.Weather))
+			}
+		}
+
+		// --- 2. Player Input, once for each pokemon in battle ---
+		var actions []game.Action
+		for len(actions) < len(b.Actives()) {
+			active := b.Actives()[len(actions)]
+			if b.Double {
+				fmt.Fprintf(ui.Out, "What will %s do?\n", active.Nickname)
+			}
+			fmt.Fprintln(ui.Out, "Choose: (1) Fight  (2) Bag  (3) Pokemon  (4) Run")
+			choice, err := ui.In.Prompt("> ")
+			if err != nil && b.Trainer != nil {
+				ui.PrintEvents(b.Forfeit())
+				return b.Outcome()
+			}
+			if err != nil {
+				choice, err = "4", nil
+			}
+
+			var action game.Action
+			switch choice {
+			case "1": // FIGHT
+				action, err = ui.fightMenu(b, active)
+			case "2": // BAG (Catching happens here)
+				action, err = ui.bagMenu(b, active)
+			case "3": // POKEMON (Switching)
+				action, err = ui.switchMenu(b)
+			case "4": // RUN
+				action = game.Action{Kind: game.ActionRun}
+			default:
+				continue
+			}
+			if err == errBack {
+				continue
+			}
+			if err != nil {
+				fmt.Fprintln(ui.Out, err)
+				continue
+			}
+			actions = append(actions, action)
+		}
+
+		// --- 3. Resolve the Turn ---
+		events, err := b.Step(actions...)
+		if err != nil {
+			fmt.Fprintf(ui.Out, "%s!\n", capitalize(err.Error()))
+			continue
+		}
+		ui.PrintEvents(events)
+	}
+	return b.Outcome()
+}
+
+// PrintEvents narrates events, pausing on ball shakes
+func (ui BattleUI) PrintEvents(events []game.Event) {
+	for _, e := range events {
+		if e.Kind == game.EventShake {
+			time.Sleep(ui.ShakeDelay)
+		}
+		fmt.Fprintln(ui.Out, FormatEvent(e))
+	}
+}
+
+func (ui BattleUI) fightMenu(b *game.Battle, active *game.BattlePokemon) (game.Action, error) {
+	for i, m := range active.Moves {
+		fmt.Fprintf(ui.Out, "%d. %s (%s) [%d/%d PP]\n", i+1, m.Name, m.Type, m.CurrentPP, m.MaxPP)
+	}
+	idx, err := ui.pick("Select move > ", len(active.Moves))
+	if err != nil {
+		return game.Action{}, err
+	}
+	action := game.Action{Kind: game.ActionMove, Move: idx}
+
+	// Only ask who to aim at when there's a choice
+	foes := b.Foes()
+	if len(foes) > 1 && active.Moves[idx].NeedsTarget() {
+		fmt.Fprintln(ui.Out, "Target which Pokemon?")
+		for i, foe := range foes {
+			fmt.Fprintf(ui.Out, "%d. %s (%d/%d HP)\n", i+1, b.Name(foe), foe.Stats.HP, foe.Stats.MaxHP)
+		}
+		action.FoeTarget, err = ui.pick("> ", len(foes))
+		if err != nil {
+			return game.Action{}, err
+		}
+	}
+	return action, nil
+}
+
+func (ui BattleUI) bagMenu(b *game.Battle, active *game.BattlePokemon) (game.Action, error) {
+	inv := b.Inventory
+	fmt.Fprintln(ui.Out, "\n--- BAG ---")
+	fmt.Fprintln(ui.Out, "1. Pokeballs")
+	fmt.Fprintln(ui.Out, "2. Healing Items")
+	fmt.Fprintln(ui.Out, "3. Cancel")
+
+	category, err := ui.In.Prompt("Select category > ")
+	if err != nil {
+		return game.Action{}, errBack
+	}
+
+	switch category {
+	case "1": // POKEBALL SUB-MENU
+		fmt.Fprintln(ui.Out, "\n--- POKEBALLS ---")
+		var balls []game.Ball
+		for _, ball := range game.Balls {
+			// The classic balls are always listed, specialty ones only once owned
+			if inv.BallCount(ball.Name) > 0 || !isSpecialtyBall(ball.Name) {
+				balls = append(balls, ball)
+				fmt.Fprintf(ui.Out, "%d. %s (x%d)\n", len(balls), ball.Label, inv.BallCount(ball.Name))
+			}
+		}
+		fmt.Fprintf(ui.Out, "%d. Back\n", len(balls)+1)
+
+		idx, err := ui.pick("Select ball > ", len(balls))
+		if err != nil {
+			return game.Action{}, err
+		}
+		return game.Action{Kind: game.ActionItem, Item: balls[idx].Name}, nil
+
+	case "2": // HEALING SUB-MENU
+		fmt.Fprintln(ui.Out, "\n--- HEALING ---")
+		fmt.Fprintf(ui.Out, "1. Potion (x%d) [+20 HP]\n", inv.Potions)
+		fmt.Fprintf(ui.Out, "2. Super Potion (x%d) [+50 HP]\n", inv.SuperPotions)
+		fmt.Fprintf(ui.Out, "3. Revive (x%d) [Restores Fainted]\n", inv.Revives)
+		fmt.Fprintln(ui.Out, "4. Back")
+
+		itemChoice, err := ui.In.Prompt("Select item > ")
+		if err != nil {
+			return game.Action{}, errBack
+		}
+
+		switch itemChoice {
+		case "1", "2":
+			item := "potion"
+			if itemChoice == "2" {
+				item = "superpotion"
+			}
+			return game.Action{Kind: game.ActionItem, Item: item, Target: partyIndex(b, active)}, nil
+
+		case "3": // REVIVE LOGIC
+			fmt.Fprintln(ui.Out, "Revive which Pokemon?")
+			for i, p := range b.Party {
+				fmt.Fprintf(ui.Out, "%d. %s (%s)\n", i+1, p.Nickname, p.Status)
+			}
+			idx, err := ui.pick("> ", len(b.Party))
+			if err != nil {
+				return game.Action{}, err
+			}
+			return game.Action{Kind: game.ActionItem, Item: "revive", Target: idx}, nil
+		}
+	}
+
+	return game.Action{}, errBack
+}
+
+func (ui BattleUI) switchMenu(b *game.Battle) (game.Action, error) {
+	fmt.Fprintln(ui.Out, "Select Pokemon:")
+	for i, p := range b.Party {
+		fmt.Fprintf(ui.Out, "%d. %s (%d/%d HP)\n", i+1, p.Nickname, p.Stats.HP, p.Stats.MaxHP)
+	}
+	idx, err := ui.pick("> ", len(b.Party))
+	if err != nil {
+		return game.Action{}, err
+	}
+	return game.Action{Kind: game.ActionSwitch, Target: idx}, nil
+}
+
+// replaceMenu asks who to send out after a faint. Without input to
+// read, e.g. in scripted runs, the first healthy pokemon goes in.
+func (ui BattleUI) replaceMenu(b *game.Battle, pos int) {
+	fmt.Fprintln(ui.Out, "\nChoose a Pokemon to send out:")
+	for i, p := range b.Party {
+		fmt.Fprintf(ui.Out, "%d. %s (Lvl %d) %d/%d HP %s [%s]%s\n", i+1, p.Nickname, p.Level, p.Stats.HP, p.Stats.MaxHP, p.Status, strings.Join(p.TypeNames(), "/"), matchupLabel(b, p))
+	}
+
+	answer, err := ui.In.Prompt("> ")
+	idx := b.DefaultReplacement()
+	if err == nil {
+		choice, convErr := strconv.Atoi(answer)
+		if convErr != nil || choice < 1 || choice > len(b.Party) {
+			fmt.Fprintln(ui.Out, "Invalid selection!")
+			return
+		}
+		idx = choice - 1
+	}
+
+	events, err := b.Replace(pos, idx)
+	if err != nil {
+		fmt.Fprintf(ui.Out, "%s!\n", capitalize(err.Error()))
+		return
+	}
+	ui.PrintEvents(events)
+}
+
+// matchupLabel sums up how a benched pokemon fares against the foes on the field,
+// e.g. " - takes x2 from Foe geodude, hits it x0.5"
+func matchupLabel(b *game.Battle, p *game.BattlePokemon) string {
+	if p.Status == game.StatusFainted {
+		return ""
+	}
+	var parts []string
+	for _, foe := range b.Foes() {
+		takes := 0.0
+		for _, t := range foe.TypeNames() {
+			takes = max(takes, game.GetTypeEffectiveness(t, p.TypeNames()))
+		}
+		hits := 0.0
+		for _, m := range p.Moves {
+			if m.CurrentPP > 0 && m.Power > 0 {
+				hits = max(hits, game.GetTypeEffectiveness(m.Type, foe.TypeNames()))
+			}
+		}
+		parts = append(parts, fmt.Sprintf("takes x%g from %s, hits it x%g", takes, b.Name(foe), hits))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " - " + strings.Join(parts, "; ")
+}
+
+// pick reads a 1-based menu choice and returns it 0-based
+func (ui BattleUI) pick(label string, options int) (int, error) {
+	answer, err := ui.In.Prompt(label)
+	if err != nil {
+		return 0, errBack
+	}
+	idx, err := strconv.Atoi(answer)
+	if err != nil || idx < 1 || idx > options {
+		return 0, errBack
+	}
+	return idx - 1, nil
+}
+
+func partyIndex(b *game.Battle, active *game.BattlePokemon) int {
+	for i, p := range b.Party {
+		if p == active {
+			return i
+		}
+	}
+	return 0
+}
+
+func isSpecialtyBall(name string) bool {
+	switch name {
+	case "pokeball", "greatball", "ultraball", "masterball":
+		return false
+	}
+	return true
+}
+
+// PlayReplay narrates a recorded battle, waiting delay between events
+func (ui BattleUI) PlayReplay(r game.Replay, delay time.Duration) {
+	fmt.Fprintf(ui.Out, "Replay recorded %s\n", r.Recorded.Format("2006-01-02 15:04"))
+	for i, e := range r.Events {
+		if i > 0 {
+			time.Sleep(delay)
+		}
+		fmt.Fprintln(ui.Out, FormatEvent(e))
+	}
+	fmt.Fprintf(ui.Out, "\n--- %s vs %s: %s after %d turns ---\n", r.Player, r.Opponent, r.Outcome, r.Turns)
+}
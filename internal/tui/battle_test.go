@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+)
+
+func newTestMon(name string, level, speed int) *game.BattlePokemon {
+	p := &game.BattlePokemon{
+		Nickname: name,
+		Level:    level,
+		Stats:    game.Stats{HP: 50, MaxHP: 50, Attack: 20, Defense: 20, Speed: speed},
+		Moves:    []game.Move{{Name: "growl", Type: "normal", Accuracy: 100, CurrentPP: 40, MaxPP: 40}},
+	}
+	p.Base.Name = name
+	return p
+}
+
+// runWithTimeout plays a battle with no input at all, failing if it never ends
+func runWithTimeout(t *testing.T, b *game.Battle) game.BattleOutcome {
+	t.Helper()
+	ui := BattleUI{In: NewScannerPrompter(strings.NewReader(""), io.Discard), Out: io.Discard}
+	done := make(chan game.BattleOutcome, 1)
+	go func() { done <- ui.Run(b) }()
+	select {
+	case outcome := <-done:
+		return outcome
+	case <-time.After(2 * time.Second):
+		t.Fatalf("battle didn't end once input ran out")
+		return game.OutcomeOngoing
+	}
+}
+
+func TestRunFleesWithoutInput(t *testing.T) {
+	b, _, err := game.NewBattle(game.BattleConfig{
+		Party: []*game.BattlePokemon{newTestMon("slowpoke", 5, 5)},
+		Wild:  newTestMon("pidgey", 5, 50),
+		RNG:   game.NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+
+	if outcome := runWithTimeout(t, b); outcome != game.OutcomeFled {
+		t.Errorf("expected to flee, got %v", outcome)
+	}
+}
+
+func TestRunForfeitsTrainerBattleWithoutInput(t *testing.T) {
+	trainer := &game.Trainer{ID: "joey", Name: "Joey", Class: "youngster"}
+	b, _, err := game.NewBattle(game.BattleConfig{
+		Party:       []*game.BattlePokemon{newTestMon("slowpoke", 5, 5)},
+		Trainer:     trainer,
+		TrainerTeam: []*game.BattlePokemon{newTestMon("rattata", 3, 50)},
+		RNG:         game.NewRNG(1),
+	})
+	if err != nil {
+		t.Fatalf("NewBattle: %v", err)
+	}
+
+	if outcome := runWithTimeout(t, b); outcome != game.OutcomeLost {
+		t.Errorf("expected to forfeit, got %v", outcome)
+	}
+	if log := b.Log(); log[len(log)-1].Kind != game.EventForfeit {
+		t.Errorf("expected the battle to end with a forfeit, got %v", log[len(log)-1])
+	}
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+)
+
+// FormatEvent turns a battle event into the line shown to the player
+func FormatEvent(e game.Event) string {
+	switch e.Kind {
+	case game.EventChallenge:
+		return fmt.Sprintf("%s would like to battle!", e.Actor)
+	case game.EventBattleStart:
+		return fmt.Sprintf("\n--- BATTLE STARTED: %s vs %s ---", e.Actor, e.Target)
+	case game.EventDisobey:
+		return fmt.Sprintf("%s is loafing around! It won't obey!", e.Actor)
+	case game.EventMove:
+		return fmt.Sprintf("%s used %s!", e.Actor, e.Move)
+	case game.EventNoTarget:
+		return "...but there was no target!"
+	case game.EventFailed:
+		return "But it failed!"
+	case game.EventMiss:
+		return "...but it missed!"
+	case game.EventDamage:
+		return fmt.Sprintf("Dealt %d damage to %s.", e.Amount, e.Target)
+	case game.EventCrit:
+		return "A critical hit!"
+	case game.EventEffectiveness:
+		switch {
+		case e.Multiplier == 0:
+			return fmt.Sprintf("It doesn't affect %s...", e.Target)
+		case e.Multiplier > 1:
+			return "It's super effective!"
+		default:
+			return "It's not very effective..."
+		}
+	case game.EventStatus:
+		return fmt.Sprintf("%s was afflicted with %s!", e.Target, e.Status)
+	case game.EventCantMove:
+		if e.Status == game.StatusFreeze {
+			return fmt.Sprintf("%s is frozen solid!", e.Actor)
+		}
+		return fmt.Sprintf("%s is fast asleep.", e.Actor)
+	case game.EventStatusEnd:
+		if e.Status == game.StatusFreeze {
+			return fmt.Sprintf("%s thawed out!", e.Target)
+		}
+		return fmt.Sprintf("%s woke up!", e.Target)
+	case game.EventHeal:
+		switch e.Item {
+		case "leftovers":
+			return fmt.Sprintf("%s restored a little HP using its Leftovers!", e.Target)
+		case "potion", "superpotion":
+			if e.Actor != "" {
+				return fmt.Sprintf("%s used a %s! %s's HP is now %d/%d", e.Actor, itemLabel(e.Item), e.Target, e.HP, e.MaxHP)
+			}
+			return fmt.Sprintf("Used %s! %s's HP is now %d/%d", itemLabel(e.Item), e.Target, e.HP, e.MaxHP)
+		default:
+			return fmt.Sprintf("%s ate its %s and restored HP!", e.Target, e.Item)
+		}
+	case game.EventCure:
+		return fmt.Sprintf("%s ate its %s and was cured of %s!", e.Target, e.Item, e.Status)
+	case game.EventRevive:
+		return fmt.Sprintf("%s was revived to half HP!", e.Target)
+	case game.EventBallThrown:
+		return fmt.Sprintf("You threw a %s!", game.BallLabel(e.Item))
+	case game.EventShake:
+		return "...shake..."
+	case game.EventCaught:
+		return fmt.Sprintf("Gotcha! The %s was caught!", e.Target)
+	case game.EventBreakFree:
+		return breakFreeMessage(e.Amount)
+	case game.EventFaint:
+		return fmt.Sprintf("%s fainted!", e.Target)
+	case game.EventSwitch:
+		if e.Side == game.SideOpponent {
+			return fmt.Sprintf("%s sent out %s!", e.Actor, e.Target)
+		}
+		return fmt.Sprintf("Go! %s!", e.Target)
+	case game.EventRun:
+		switch {
+		case e.Item == "smoke-ball":
+			return fmt.Sprintf("%s fled using its Smoke Ball!", e.Actor)
+		case e.Ability == "run-away":
+			return fmt.Sprintf("%s's Run Away: Got away safely!", e.Actor)
+		}
+		return "Got away safely!"
+	case game.EventRunFailed:
+		return "Can't escape!"
+	case game.EventTrainerDefeated:
+		return fmt.Sprintf("You defeated %s!", e.Actor)
+	case game.EventMoney:
+		return fmt.Sprintf("You received ₽%d for winning!", e.Amount)
+	case game.EventXP:
+		return fmt.Sprintf("%s gained %d XP!", e.Target, e.Amount)
+	case game.EventLevelUp:
+		return fmt.Sprintf("%s grew to Level %d!", e.Target, e.Amount)
+	case game.EventEvolving:
+		return fmt.Sprintf("\n...Wait! %s is evolving!", e.Target)
+	case game.EventEvolved:
+		return fmt.Sprintf("Congratulations! Your %s evolved into %s!", e.Target, e.Species)
+	case game.EventEvolveFailed:
+		return "Evolution failed due to connection error."
+	case game.EventWeatherStart:
+		if e.Ability != "" {
+			return fmt.Sprintf("%s's %s: %s", e.Actor, abilityLabel(e.Ability), weatherMessages[e.Weather][0])
+		}
+		return weatherMessages[e.Weather][0]
+	case game.EventWeather:
+		return weatherMessages[e.Weather][1]
+	case game.EventWeatherEnd:
+		return weatherMessages[e.Weather][2]
+	case game.EventWeatherDamage:
+		if e.Weather == game.WeatherHail {
+			return fmt.Sprintf("%s is pelted by hail!", e.Target)
+		}
+		return fmt.Sprintf("%s is buffeted by the sandstorm!", e.Target)
+	case game.EventSideCondition:
+		return fmt.Sprintf(sideConditionMessages[e.Move][0], sideLabel(e.Side))
+	case game.EventSideConditionEnd:
+		if e.Target != "" {
+			return fmt.Sprintf("%s absorbed the poison spikes!", e.Target)
+		}
+		return fmt.Sprintf(sideConditionMessages[e.Move][1], capitalize(sideLabel(e.Side)))
+	case game.EventHazardDamage:
+		return fmt.Sprintf("%s was hurt by the hazards! (%d/%d HP)", e.Target, e.HP, e.MaxHP)
+	case game.EventBlackout:
+		return "You blacked out..."
+	case game.EventForfeit:
+		return fmt.Sprintf("You gave up the battle against %s.", e.Actor)
+	default:
+		return string(e.Kind)
+	}
+}
+
+// weatherMessages are the start, ongoing and end lines for each weather
+var weatherMessages = map[game.Weather][3]string{
+	game.WeatherRain:      {"It started to rain!", "Rain continues to fall.", "The rain stopped."},
+	game.WeatherSun:       {"The sunlight turned harsh!", "The sunlight is strong.", "The harsh sunlight faded."},
+	game.WeatherSandstorm: {"A sandstorm kicked up!", "The sandstorm rages.", "The sandstorm subsided."},
+	game.WeatherHail:      {"It started to hail!", "Hail continues to fall.", "The hail stopped."},
+}
+
+// sideConditionMessages are the lines for a condition going up and wearing off
+var sideConditionMessages = map[string][2]string{
+	game.ConditionStealthRock: {"Pointed stones float in the air around %s!", "The pointed stones around %s disappeared."},
+	game.ConditionSpikes:      {"Spikes were scattered around the feet of %s!", "The spikes around %s disappeared."},
+	game.ConditionToxicSpikes: {"Poison spikes were scattered around the feet of %s!", "The poison spikes around %s disappeared."},
+	game.ConditionReflect:     {"Reflect made %s stronger against physical moves!", "%s's Reflect wore off!"},
+	game.ConditionLightScreen: {"Light Screen made %s stronger against special moves!", "%s's Light Screen wore off!"},
+}
+
+func sideLabel(side string) string {
+	if side == game.SideOpponent {
+		return "the opposing team"
+	}
+	return "your team"
+}
+
+// SideConditionsLabel lists a side's hazards and screens for the HUD, e.g. "Spikes x2, Reflect (3 turns)"
+func SideConditionsLabel(conditions game.SideConditions) string {
+	var labels []string
+	for _, c := range []string{game.ConditionStealthRock, game.ConditionSpikes, game.ConditionToxicSpikes, game.ConditionReflect, game.ConditionLightScreen} {
+		n := conditions[c]
+		switch {
+		case n == 0:
+			continue
+		case game.IsScreen(c):
+			labels = append(labels, fmt.Sprintf("%s (%d turns)", abilityLabel(c), n))
+		case n > 1:
+			labels = append(labels, fmt.Sprintf("%s x%d", abilityLabel(c), n))
+		default:
+			labels = append(labels, abilityLabel(c))
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// WeatherLabel is the weather's name for the HUD
+func WeatherLabel(w game.Weather) string {
+	if w == game.WeatherSun {
+		return "Harsh sunlight"
+	}
+	return capitalize(string(w))
+}
+
+// abilityLabel turns API names like "sand-stream" into "Sand Stream"
+func abilityLabel(ability string) string {
+	words := strings.Split(ability, "-")
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, " ")
+}
+
+func breakFreeMessage(shakes int) string {
+	switch shakes {
+	case 0:
+		return "Oh no! The Pokemon broke free!"
+	case 1:
+		return "Aww! It appeared to be caught!"
+	case 2:
+		return "Aargh! Almost had it!"
+	default:
+		return "Gah! It was so close, too!"
+	}
+}
+
+func itemLabel(item string) string {
+	if item == "superpotion" {
+		return "Super Potion"
+	}
+	return capitalize(item)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
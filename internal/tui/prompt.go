@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// Prompter asks the player for one line of input
+type Prompter interface {
+	Prompt(label string) (string, error)
+}
+
+// ReadlinePrompter shares the REPL's readline instance, so menus
+// don't fight with it over stdin
+type ReadlinePrompter struct {
+	RL         *readline.Instance
+	MainPrompt string // Restored after each prompt
+}
+
+func (p ReadlinePrompter) Prompt(label string) (string, error) {
+	p.RL.SetPrompt(label)
+	defer p.RL.SetPrompt(p.MainPrompt)
+
+	line, err := p.RL.Readline()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ScannerPrompter reads answers from any reader, e.g. a script or a test
+type ScannerPrompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func NewScannerPrompter(in io.Reader, out io.Writer) *ScannerPrompter {
+	return &ScannerPrompter{
+		scanner: bufio.NewScanner(in),
+		out:     out,
+	}
+}
+
+func (p *ScannerPrompter) Prompt(label string) (string, error) {
+	fmt.Fprint(p.out, label)
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return strings.TrimSpace(p.scanner.Text()), nil
+}
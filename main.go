@@ -1,11 +1,12 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"math/rand"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/Bloodisck/bootdev-pokedex/internal/game"
 	pokeapi "github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+	"github.com/Bloodisck/bootdev-pokedex/internal/tui"
 )
 
 type Config struct {
@@ -22,9 +24,31 @@ type Config struct {
 	Pokeapi       pokeapi.Client
 	CaughtPokemon map[string]pokeapi.Pokemon
 	VisibleAreas  []string
-	Party         []*game.BattlePokemon
-	PC            []*game.BattlePokemon
-	Inventory     game.PlayerInventory
+	// Location of the last area explored, used for location-based evolutions
+	CurrentLocation string
+	Party           []*game.BattlePokemon
+	PC              []*game.BattlePokemon
+	Inventory       game.PlayerInventory
+	// Input answers menu prompts; it shares the REPL's readline
+	Input tui.Prompter
+	// RNG is the session's only source of randomness, see --seed
+	RNG *game.RNG
+	// Trainers who can be challenged, and the ids of those already beaten
+	Trainers         []game.Trainer
+	DefeatedTrainers map[string]bool
+	// The gyms in order, and the badges earned from them
+	Gyms   []game.Gym
+	Badges game.Badges
+	// CurrentArea is the location area being explored, whose wild pokemon can be encountered
+	CurrentArea string
+	// CurrentRegion is the region CurrentLocation belongs to, e.g. kanto
+	CurrentRegion string
+	// Wild is the pokemon the player just ran into, nil until they walk into one
+	Wild *wildEncounter
+	// Clock is the in-game time, see --clock-speed
+	Clock game.Clock
+	// Steps walked with the party, who grow closer every game.FriendshipWalkSteps
+	Steps int
 }
 
 type cliCommand struct {
@@ -33,20 +57,81 @@ type cliCommand struct {
 	callback    func(*Config, []string) error
 }
 
+const replPrompt = "Pokedex > "
+
 func main() {
+	// A seeded session stops the clock and keeps the save it started from, so it
+	// plays out the same when replayed from that save with the same seed and commands.
+	// A new seeded game replays from no save at all.
+	seed := flag.Int64("seed", 0, "seed for the random number generator, to reproduce a session from the save it started with (0 picks one)")
+	clockSpeed := flag.Float64("clock-speed", 0, "game minutes that pass each real minute (0 follows the real clock)")
+	flag.Parse()
+
+	rng := game.NewRandomRNG()
+	if *seed != 0 {
+		rng = game.NewRNG(*seed)
+	}
+
 	pokeClient := pokeapi.NewClient(5*time.Second, 5*time.Minute)
 
+	// Headless modes skip the save file and the REPL
+	if flag.Arg(0) == "simulate" {
+		if err := runSimulate(pokeClient, rng, flag.Args()[1:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:      replPrompt,
+		HistoryFile: "/tmp/pokedex_history.tmp",
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer rl.Close()
+
 	cfg := &Config{
 		Pokeapi: pokeClient,
+		Input:   tui.ReadlinePrompter{RL: rl, MainPrompt: replPrompt},
+		RNG:     rng,
+		Clock:   game.NewClock(*clockSpeed, time.Now(), time.Now()),
+	}
+	if *seed != 0 {
+		if *clockSpeed != 0 {
+			fmt.Println("--clock-speed is ignored with --seed, which stops the clock.")
+		}
+		cfg.Clock = game.StoppedClock(seededStartTime)
 	}
 
+	trainers, err := game.LoadTrainers()
+	if err != nil {
+		panic(err)
+	}
+	cfg.Trainers = trainers
+
+	gyms, err := game.LoadGyms()
+	if err != nil {
+		panic(err)
+	}
+	cfg.Gyms = gyms
+
 	loadGame(cfg)
 
-	startRepl(cfg)
+	startRepl(cfg, rl)
 }
 
 const saveFilePath = "savegame.json"
 
+// seededStartTime is when the clock stops for a new seeded game
+var seededStartTime = time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// sessionSavePath is where a seeded session keeps the save it started from, to replay it with
+func sessionSavePath(seed int64) string {
+	return fmt.Sprintf("savegame-%d.json", seed)
+}
+
 func saveGame(cfg *Config) error {
 	// We create a temporary struct to hold EVERYTHING we want to save
 	type SaveData struct {
@@ -54,6 +139,14 @@ func saveGame(cfg *Config) error {
 		Party         []*game.BattlePokemon      `json:"party"`
 		PC            []*game.BattlePokemon      `json:"pc"`
 		Inventory     game.PlayerInventory       `json:"inventory"`
+		Seed          int64                      `json:"seed"` // Seed of the session that saved, see --seed
+		Defeated      map[string]bool            `json:"defeated_trainers"`
+		Badges        game.Badges                `json:"badges"`
+		CurrentArea   string                     `json:"current_area,omitempty"`
+		Location      string                     `json:"current_location,omitempty"`
+		Region        string                     `json:"current_region,omitempty"`
+		GameTime      time.Time                  `json:"game_time"`
+		Steps         int                        `json:"steps"`
 	}
 
 	data := SaveData{
@@ -61,6 +154,14 @@ func saveGame(cfg *Config) error {
 		Party:         cfg.Party,
 		PC:            cfg.PC,
 		Inventory:     cfg.Inventory,
+		Seed:          cfg.RNG.Seed(),
+		Defeated:      cfg.DefeatedTrainers,
+		Badges:        cfg.Badges,
+		CurrentArea:   cfg.CurrentArea,
+		Location:      cfg.CurrentLocation,
+		Region:        cfg.CurrentRegion,
+		GameTime:      cfg.Clock.At(time.Now()),
+		Steps:         cfg.Steps,
 	}
 
 	fileData, err := json.MarshalIndent(data, "", "  ")
@@ -75,6 +176,8 @@ func loadGame(cfg *Config) {
 	cfg.CaughtPokemon = make(map[string]pokeapi.Pokemon)
 	cfg.Party = []*game.BattlePokemon{}
 	cfg.PC = []*game.BattlePokemon{}
+	cfg.DefeatedTrainers = make(map[string]bool)
+	cfg.Badges = make(game.Badges)
 	if cfg.Inventory.EvolutionStones == nil {
 		cfg.Inventory.EvolutionStones = make(map[string]int)
 	}
@@ -99,6 +202,14 @@ func loadGame(cfg *Config) {
 		Party         []*game.BattlePokemon      `json:"party"`
 		PC            []*game.BattlePokemon      `json:"pc"`
 		Inventory     game.PlayerInventory       `json:"inventory"`
+		Seed          int64                      `json:"seed"` // Seed of the session that saved, see --seed
+		Defeated      map[string]bool            `json:"defeated_trainers"`
+		Badges        game.Badges                `json:"badges"`
+		CurrentArea   string                     `json:"current_area,omitempty"`
+		Location      string                     `json:"current_location,omitempty"`
+		Region        string                     `json:"current_region,omitempty"`
+		GameTime      time.Time                  `json:"game_time"`
+		Steps         int                        `json:"steps"`
 	}
 
 	var loadedData SaveData
@@ -113,10 +224,50 @@ func loadGame(cfg *Config) {
 	cfg.Party = loadedData.Party
 	cfg.PC = loadedData.PC
 	cfg.Inventory = loadedData.Inventory
+	if loadedData.Defeated != nil {
+		cfg.DefeatedTrainers = loadedData.Defeated
+	}
+	if loadedData.Badges != nil {
+		cfg.Badges = loadedData.Badges
+	}
+	cfg.CurrentArea = loadedData.CurrentArea
+	cfg.CurrentLocation = loadedData.Location
+	cfg.CurrentRegion = loadedData.Region
+	cfg.Steps = loadedData.Steps
+	// Pokemon saved before friendship existed start at their species' base friendship
+	for _, p := range cfg.Party {
+		p.EnsureFriendship(&cfg.Pokeapi)
+	}
+	for _, p := range cfg.PC {
+		p.EnsureFriendship(&cfg.Pokeapi)
+	}
+	// A sped up clock carries on from where the last session left it
+	if cfg.Clock.Speed > 0 && !loadedData.GameTime.IsZero() {
+		cfg.Clock = game.NewClock(cfg.Clock.Speed, loadedData.GameTime, time.Now())
+	}
+
+	if loadedData.Seed != 0 {
+		fmt.Printf("Last session's seed: %d\n", loadedData.Seed)
+	}
+	if !cfg.Clock.Stopped() {
+		fmt.Printf("This session's seed: %d. Start with --seed to make a session replayable.\n", cfg.RNG.Seed())
+		return
+	}
+
+	// A seeded session stops the clock where the save left it and keeps the starting save
+	if !loadedData.GameTime.IsZero() {
+		cfg.Clock = game.StoppedClock(loadedData.GameTime)
+	}
+	sessionPath := sessionSavePath(cfg.RNG.Seed())
+	if err := os.WriteFile(sessionPath, fileData, 0644); err != nil {
+		fmt.Printf("Couldn't keep the starting save for replays: %v\n", err)
+		return
+	}
+	fmt.Printf("Seeded session: the clock is stopped in the %s. To replay it, copy %s over %s and start with --seed %d.\n",
+		timeOfDay(cfg), sessionPath, saveFilePath, cfg.RNG.Seed())
 }
 
 func runNewGameSequence(cfg *Config) {
-	reader := bufio.NewScanner(os.Stdin)
 	fmt.Println("Welcome to the world of Pokémon!")
 	fmt.Println("It looks like you're new here. Please choose your starter:")
 	fmt.Println("1. Charmander")
@@ -131,9 +282,11 @@ func runNewGameSequence(cfg *Config) {
 
 	var choice string
 	for {
-		fmt.Print("Enter number (1-3): ")
-		reader.Scan()
-		input := reader.Text()
+		input, err := cfg.Input.Prompt("Enter number (1-3): ")
+		if err != nil {
+			fmt.Println("Closing the Pokedex... Goodbye!")
+			os.Exit(0)
+		}
 
 		if name, ok := starters[input]; ok {
 			choice = name
@@ -152,7 +305,7 @@ func runNewGameSequence(cfg *Config) {
 	}
 
 	// Create the Battle instance (Starting at Level 5)
-	starter, err := game.NewBattlePokemon(pokemonBase, 5, cfg.Pokeapi)
+	starter, err := game.NewBattlePokemon(pokemonBase, 5, cfg.Pokeapi, cfg.RNG)
 	if err != nil {
 		fmt.Printf("Error generating pokemon: %v\n", err)
 	}
@@ -173,17 +326,9 @@ func cleanInput(text string) []string {
 	return pokemon
 }
 
-func startRepl(cfg *Config) {
-	rl, err := readline.NewEx(&readline.Config{
-		Prompt:      "Pokedex > ",
-		HistoryFile: "/tmp/pokedex_history.tmp",
-	})
-	if err != nil {
-		panic(err)
-	}
-	defer rl.Close()
-
+func startRepl(cfg *Config, rl *readline.Instance) {
 	for {
+		rl.SetPrompt(fmt.Sprintf("Pokedex [%s] > ", timeOfDay(cfg)))
 		line, err := rl.Readline()
 		if err != nil {
 			break
@@ -228,18 +373,35 @@ func commandHelp(cfg *Config, args []string) error {
 		"mapb",
 		"left",
 		"right",
+		"travel",
+		"where",
 		"explore",
+		"walk",
+		"surf",
+		"fish",
+		"repel",
+		"encounter",
 		"bag",
 		"heal",
 		"evolve",
+		"trade",
 		"shop",
+		"expshare",
 		"catch",
 		"battle",
+		"trainers",
+		"challenge",
+		"gym",
+		"badges",
+		"replay",
 		"team",
 		"addteam",
 		"deposit",
 		"withdraw",
+		"give",
+		"take",
 		"inspect",
+		"calc",
 		"pokedex",
 		"exit",
 	}
@@ -256,11 +418,18 @@ func commandHelp(cfg *Config, args []string) error {
 }
 
 func commandExplore(config *Config, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: explore <name or number>")
+	if len(args) > 1 {
+		return fmt.Errorf("usage: explore [name or number]")
 	}
 
-	target := args[0]
+	// Explore where we are unless told otherwise
+	target := config.CurrentArea
+	if len(args) == 1 {
+		target = args[0]
+	}
+	if target == "" {
+		return fmt.Errorf("you're not anywhere yet. Use 'travel' to pick somewhere to go")
+	}
 
 	if index, err := strconv.Atoi(target); err == nil {
 		realIndex := index - 1
@@ -278,11 +447,13 @@ func commandExplore(config *Config, args []string) error {
 		return err
 	}
 
-	fmt.Println("Found Pokemon:")
-	for _, encounter := range locationDetail.PokemonEncounters {
-		fmt.Printf(" - %s\n", encounter.Pokemon.Name)
+	printEncounterTable(game.NewEncounterTable(locationDetail, game.DefaultVersion))
+	// Exploring somewhere else only previews it; getting there is travel's job
+	if locationDetail.Name != config.CurrentArea {
+		fmt.Printf("Use 'travel %s' to go there.\n", locationDetail.Name)
+		return nil
 	}
-
+	fmt.Println("Use 'walk' to look for wild Pokemon.")
 	return nil
 }
 
@@ -334,48 +505,27 @@ func commandLeft(cfg *Config, args []string) error {
 }
 
 func commandCatch(cfg *Config, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: encounter <pokemon_name>")
+	if len(args) > 1 {
+		return fmt.Errorf("usage: catch [pokemon_name]")
 	}
-
-	// 1. Fetch wild pokemon data
-	name := args[0]
-	wildBase, err := cfg.Pokeapi.GetPokemon(name)
-	if err != nil {
-		return err
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
 	}
 
-	// 2. Create Wild Instance (Random level 1-10)
-	wildMon, err := game.NewBattlePokemon(wildBase, rand.Intn(5)+1, cfg.Pokeapi)
+	// 1. Only the wild pokemon the player ran into can be caught
+	wild, err := currentWild(cfg, name)
 	if err != nil {
-		return fmt.Errorf("failed to create pokemon: %w", err)
+		return err
 	}
 
-	fmt.Printf("A wild %s appeared!\n", wildBase.Name)
-
-	// 3. Start Battle Loop
-	// We pass the party, the wild mon, and the inventory
-	// We also pass the PC so the battle engine can add the pokemon there if the party is full
-	caught := game.StartBattle(cfg.Party, wildMon, &cfg.Inventory, cfg.Pokeapi)
-
-	if caught {
-		// If the boolean returned true, it means the catch was successful
-		// We add the base data to our CaughtPokemon (the Pokedex tracker)
-		cfg.CaughtPokemon[name] = wildBase
-		if len(cfg.Party) < 6 {
-			cfg.Party = append(cfg.Party, wildMon)
-			fmt.Println("Added to your party!")
-		} else {
-			cfg.PC = append(cfg.PC, wildMon)
-			fmt.Println("Party full. Transferred to PC Box 1!")
-		}
-		saveGame(cfg)
-
-		// Logic check: StartBattle should have handled appending 'wildMon'
-		// to either cfg.Party or cfg.PC already.
+	// 2. Start Battle Loop
+	cfg.Wild = nil
+	if err := runWildBattle(cfg, wild.Base, wild.Pokemon, game.RandomAI{}); err != nil {
+		return err
 	}
 
-	// 4. Save the entire game state (Party, PC, Inventory, Pokedex)
+	// 3. Save the entire game state (Party, PC, Inventory, Pokedex)
 	err = saveGame(cfg)
 	if err != nil {
 		return fmt.Errorf("battle finished but failed to save: %w", err)
@@ -438,10 +588,17 @@ func printBattlePokemonDetails(p *game.BattlePokemon, location string) {
 	fmt.Printf("HP:  %d/%d\n", p.Stats.HP, p.Stats.MaxHP)
 
 	// HERE IS THE XP INDICATION YOU WANTED
-	fmt.Printf("XP:  %d / %d\n", p.XP, p.NextLevelXP)
+	fmt.Printf("XP:  %d total (%s)\n", p.XP, xpBar(p))
 
 	fmt.Printf("Status: %s\n", p.Status)
 	fmt.Printf("Nature: %s\n", p.Nature)
+	fmt.Printf("Friendship: %d - %s\n", p.Friendship, p.FriendshipDescription())
+	if p.HeldItem != "" {
+		fmt.Printf("Held Item: %s (%s)\n", p.HeldItem, game.HeldItemDescription(p.HeldItem))
+	}
+	if p.CaughtBall != "" {
+		fmt.Printf("Caught in: %s\n", game.BallLabel(p.CaughtBall))
+	}
 
 	fmt.Println("Stats:")
 	fmt.Printf("  -Attack:  %d\n", p.Stats.Attack)
@@ -466,11 +623,32 @@ func commandTeam(cfg *Config, args []string) error {
 			i+1, p.Nickname, p.Level, p.Stats.HP, p.Stats.MaxHP, p.Status)
 
 		// XP Bar visual (optional but cool)
-		fmt.Printf("   XP: %d/%d\n", p.XP, p.NextLevelXP)
+		fmt.Printf("   XP: %s\n", xpBar(p))
+		if p.HeldItem != "" {
+			fmt.Printf("   Holding: %s\n", p.HeldItem)
+		}
 	}
 	return nil
 }
 
+// xpBar renders progress through the current level, e.g. [#####-----] 120/240
+func xpBar(p *game.BattlePokemon) string {
+	const width = 20
+	current, needed := p.ExperienceProgress()
+	if needed <= 0 {
+		return "[" + strings.Repeat("#", width) + "] MAX"
+	}
+
+	filled := current * width / needed
+	if filled > width {
+		filled = width
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("#", filled), strings.Repeat("-", width-filled), current, needed)
+}
+
 func commandAddTeam(config *Config, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("usage: addteam <pokemon_name>")
@@ -488,7 +666,7 @@ func commandAddTeam(config *Config, args []string) error {
 
 	// Fix: Use 'baseData', pass 'config.Pokeapi', and handle the error
 	// We default to level 5 for team additions from the box, or you could track level in CaughtPokemon later
-	newMember, err := game.NewBattlePokemon(baseData, 5, config.Pokeapi)
+	newMember, err := game.NewBattlePokemon(baseData, 5, config.Pokeapi, config.RNG)
 	if err != nil {
 		return fmt.Errorf("failed to create team member: %w", err)
 	}
@@ -571,11 +749,143 @@ func commandWithdraw(cfg *Config, args []string) error {
 
 func commandBag(cfg *Config, args []string) error {
 	fmt.Println("--- Inventory ---")
-	fmt.Printf("Pokeballs: %d\n", cfg.Inventory.Pokeballs)
+	for _, b := range game.Balls {
+		if count := cfg.Inventory.BallCount(b.Name); count > 0 {
+			fmt.Printf("%s: %d\n", b.Label, count)
+		}
+	}
 	fmt.Printf("Potions:   %d\n", cfg.Inventory.Potions)
+	if cfg.Inventory.ExpShare {
+		state := "OFF"
+		if cfg.Inventory.ExpShareOn {
+			state = "ON"
+		}
+		fmt.Printf("Exp. Share: %s\n", state)
+	}
+	for item, count := range cfg.Inventory.HeldItems {
+		if count > 0 {
+			fmt.Printf("%s: %d\n", item, count)
+		}
+	}
+	for item, count := range cfg.Inventory.Repels {
+		if count > 0 {
+			fmt.Printf("%s: %d\n", item, count)
+		}
+	}
+	if cfg.Inventory.RepelSteps > 0 {
+		fmt.Printf("Repel active: %d steps left\n", cfg.Inventory.RepelSteps)
+	}
+	for _, rod := range game.Rods {
+		if cfg.Inventory.Rods[rod] {
+			fmt.Println(rod)
+		}
+	}
+	return nil
+}
+
+func findPartyOrPC(cfg *Config, name string) *game.BattlePokemon {
+	for _, p := range cfg.Party {
+		if p.Nickname == name || p.Base.Name == name {
+			return p
+		}
+	}
+	for _, p := range cfg.PC {
+		if p.Nickname == name || p.Base.Name == name {
+			return p
+		}
+	}
 	return nil
 }
 
+// returnItemToBag puts a held item back where it was bought from
+func returnItemToBag(inv *game.PlayerInventory, item string) {
+	if strings.HasSuffix(item, "-stone") && !game.IsHoldItem(item) {
+		if inv.EvolutionStones == nil {
+			inv.EvolutionStones = make(map[string]int)
+		}
+		inv.EvolutionStones[item]++
+		return
+	}
+	if inv.HeldItems == nil {
+		inv.HeldItems = make(map[string]int)
+	}
+	inv.HeldItems[item]++
+}
+
+func commandGive(cfg *Config, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: give <pokemon_name> <item_name>")
+	}
+	name, item := args[0], args[1]
+
+	p := findPartyOrPC(cfg, name)
+	if p == nil {
+		return fmt.Errorf("you don't have %s", name)
+	}
+
+	// Take the item out of whichever pocket has it
+	switch {
+	case cfg.Inventory.HeldItems[item] > 0:
+		cfg.Inventory.HeldItems[item]--
+	case cfg.Inventory.EvolutionStones[item] > 0:
+		cfg.Inventory.EvolutionStones[item]--
+	default:
+		return fmt.Errorf("you don't have any %s", item)
+	}
+
+	if p.HeldItem != "" {
+		returnItemToBag(&cfg.Inventory, p.HeldItem)
+		fmt.Printf("Took %s from %s and put it in the bag.\n", p.HeldItem, p.Nickname)
+	}
+	p.HeldItem = item
+	fmt.Printf("%s is now holding %s.\n", p.Nickname, item)
+	return saveGame(cfg)
+}
+
+func commandTake(cfg *Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: take <pokemon_name>")
+	}
+	name := args[0]
+
+	p := findPartyOrPC(cfg, name)
+	if p == nil {
+		return fmt.Errorf("you don't have %s", name)
+	}
+	if p.HeldItem == "" {
+		return fmt.Errorf("%s isn't holding anything", p.Nickname)
+	}
+
+	returnItemToBag(&cfg.Inventory, p.HeldItem)
+	fmt.Printf("Took %s from %s.\n", p.HeldItem, p.Nickname)
+	p.HeldItem = ""
+	return saveGame(cfg)
+}
+
+func commandExpShare(cfg *Config, args []string) error {
+	if !cfg.Inventory.ExpShare {
+		return fmt.Errorf("you don't have an Exp. Share! Buy one at the shop")
+	}
+
+	switch {
+	case len(args) == 0:
+		cfg.Inventory.ExpShareOn = !cfg.Inventory.ExpShareOn
+	case args[0] == "on":
+		cfg.Inventory.ExpShareOn = true
+	case args[0] == "off":
+		cfg.Inventory.ExpShareOn = false
+	default:
+		return fmt.Errorf("usage: expshare [on|off]")
+	}
+
+	if cfg.Inventory.ExpShareOn {
+		fmt.Println("Exp. Share turned ON. Benched party members will gain XP.")
+	} else {
+		fmt.Println("Exp. Share turned OFF.")
+	}
+	return saveGame(cfg)
+}
+
 func commandHeal(cfg *Config, args []string) error {
 	if len(cfg.Party) == 0 {
 		return fmt.Errorf("you have no Pokemon to heal")
@@ -588,6 +898,7 @@ func commandHeal(cfg *Config, args []string) error {
 
 	for _, p := range cfg.Party {
 		p.HealFull() // This uses the helper we added in internal/game/models.go
+		p.AdjustFriendship(game.FriendshipHealed)
 	}
 
 	fmt.Println("Your Pokemon are fighting fit! We hope to see you again!")
@@ -608,10 +919,10 @@ func commandEvolve(cfg *Config, args []string) error {
 	}
 	fmt.Println("c. Cancel")
 
-	reader := bufio.NewScanner(os.Stdin)
-	fmt.Print("Select Pokemon to evolve > ")
-	reader.Scan()
-	choice := reader.Text()
+	choice, err := cfg.Input.Prompt("Select Pokemon to evolve > ")
+	if err != nil {
+		return nil
+	}
 
 	if choice == "c" {
 		return nil
@@ -637,36 +948,57 @@ func commandEvolve(cfg *Config, args []string) error {
 		return fmt.Errorf("cannot find evolution chain: %w", err)
 	}
 
-	// C. Find the next stage using a helper function
-	nextStageName, minLevel, itemReq := game.FindNextEvolution(chainData.Chain, selectedMon.Base.Name)
-
-	if nextStageName == "" {
+	// C. Find every branch this species can take
+	options := game.FindEvolutions(chainData.Chain, selectedMon.Base.Name)
+	if len(options) == 0 {
 		fmt.Printf("%s cannot evolve any further.\n", selectedMon.Nickname)
 		return nil
 	}
 
-	// 3. CHECK REQUIREMENTS
-	// Check Level
-	if minLevel > 0 && selectedMon.Level < minLevel {
-		fmt.Printf("%s needs to be at least level %d to evolve into %s.\n", selectedMon.Nickname, minLevel, nextStageName)
+	// 3. CHECK REQUIREMENTS for each branch
+	ctx := evolutionContext(cfg, false)
+	fmt.Printf("%s can evolve into:\n", selectedMon.Nickname)
+	possible := 0
+	for i, option := range options {
+		unmet := option.UnmetConditions(selectedMon, ctx)
+		if len(unmet) == 0 {
+			possible++
+			fmt.Printf("%d. %s - READY\n", i+1, option)
+		} else {
+			fmt.Printf("%d. %s - needs to %s\n", i+1, option, strings.Join(unmet, ", "))
+		}
+	}
+
+	if possible == 0 {
+		fmt.Printf("%s doesn't meet the requirements for any evolution yet.\n", selectedMon.Nickname)
 		return nil
 	}
 
-	// Check Item (if required)
-	if itemReq != "" {
-		count := cfg.Inventory.EvolutionStones[itemReq]
-		if count <= 0 {
-			fmt.Printf("You need a %s to evolve into %s.\n", itemReq, nextStageName)
-			return nil
-		}
+	branchChoice, err := cfg.Input.Prompt("Select evolution (c to cancel) > ")
+	if err != nil {
+		return nil
+	}
+	if branchChoice == "c" {
+		return nil
+	}
+	branchIdx, err := strconv.Atoi(branchChoice)
+	if err != nil || branchIdx < 1 || branchIdx > len(options) {
+		return fmt.Errorf("invalid selection")
+	}
+
+	chosen := options[branchIdx-1]
+	if unmet := chosen.UnmetConditions(selectedMon, ctx); len(unmet) > 0 {
+		return fmt.Errorf("%s can't evolve into %s yet: needs to %s", selectedMon.Nickname, chosen.NextStage, strings.Join(unmet, ", "))
+	}
+	nextStageName, itemReq := chosen.NextStage, chosen.RequiredStone
 
+	if itemReq != "" {
 		fmt.Printf("Evolution requires 1x %s. ", itemReq)
 	}
 
 	// 4. CONFIRMATION
-	fmt.Printf("Evolve %s into %s? (y/n): ", selectedMon.Nickname, nextStageName)
-	reader.Scan()
-	if reader.Text() != "y" {
+	confirm, err := cfg.Input.Prompt(fmt.Sprintf("Evolve %s into %s? (y/n): ", selectedMon.Nickname, nextStageName))
+	if err != nil || confirm != "y" {
 		fmt.Println("Evolution cancelled.")
 		return nil
 	}
@@ -681,40 +1013,156 @@ func commandEvolve(cfg *Config, args []string) error {
 		return fmt.Errorf("failed to fetch new form data: %w", err)
 	}
 
-	// Consume Item if used
-	if itemReq != "" {
-		cfg.Inventory.EvolutionStones[itemReq]--
-	}
-
-	// Pass the API client so it can fetch a new move!
-	selectedMon.Evolve(newBase, cfg.Pokeapi)
+	// Consumes the stone or held item if used, and passes
+	// the API client so it can fetch a new move!
+	selectedMon.EvolveVia(chosen, newBase, ctx, cfg.Pokeapi, cfg.RNG)
 
 	fmt.Printf("Congratulations! Your Pokemon evolved into %s!\n", selectedMon.Nickname)
 	saveGame(cfg)
 	return nil
 }
 
+// timeOfDay is the period of the in-game clock right now, e.g. "night"
+func timeOfDay(cfg *Config) string {
+	return cfg.Clock.TimeOfDay(time.Now())
+}
+
+func evolutionContext(cfg *Config, trading bool) game.EvolutionContext {
+	return game.EvolutionContext{
+		Inventory: &cfg.Inventory,
+		Party:     cfg.Party,
+		Location:  cfg.CurrentLocation,
+		TimeOfDay: timeOfDay(cfg),
+		Trading:   trading,
+	}
+}
+
+func commandTrade(cfg *Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: trade <pokemon_name>")
+	}
+	name := args[0]
+
+	var traded *game.BattlePokemon
+	for _, p := range cfg.Party {
+		if p.Nickname == name || p.Base.Name == name {
+			traded = p
+			break
+		}
+	}
+	if traded == nil {
+		return fmt.Errorf("you don't have %s in your party", name)
+	}
+
+	fmt.Printf("You send %s to a friend over the link cable... and they send it right back!\n", traded.Nickname)
+
+	species, err := cfg.Pokeapi.GetPokemonSpecies(traded.Base.Name)
+	if err != nil {
+		return fmt.Errorf("cannot find species data: %w", err)
+	}
+	chainData, err := cfg.Pokeapi.GetEvolutionChain(species.EvolutionChain.URL)
+	if err != nil {
+		return fmt.Errorf("cannot find evolution chain: %w", err)
+	}
+
+	ctx := evolutionContext(cfg, true)
+	for _, option := range game.FindEvolutions(chainData.Chain, traded.Base.Name) {
+		if option.Trigger != "trade" || len(option.UnmetConditions(traded, ctx)) > 0 {
+			continue
+		}
+
+		fmt.Printf("What? %s is evolving!\n", traded.Nickname)
+		newBase, err := cfg.Pokeapi.GetPokemon(option.NextStage)
+		if err != nil {
+			return fmt.Errorf("failed to fetch new form data: %w", err)
+		}
+		traded.EvolveVia(option, newBase, ctx, cfg.Pokeapi, cfg.RNG)
+		fmt.Printf("Congratulations! Your Pokemon evolved into %s!\n", traded.Nickname)
+		return saveGame(cfg)
+	}
+
+	fmt.Println("Nothing happened.")
+	return nil
+}
+
 func commandShop(cfg *Config, args []string) error {
 	shopItems := map[string]int{
 		"pokeball":      10,
 		"greatball":     100,
 		"ultraball":     500,
+		"masterball":    50000,
+		"net-ball":      1000,
+		"quick-ball":    1000,
+		"timer-ball":    1000,
+		"dusk-ball":     1000,
+		"repeat-ball":   1000,
+		"level-ball":    1000,
+		"heal-ball":     300,
 		"potion":        300,
 		"superpotion":   700,
 		"revive":        500,
+		"repel":         350,
+		"super-repel":   500,
+		"max-repel":     700,
+		"old-rod":       1000,
+		"good-rod":      5000,
+		"super-rod":     15000,
 		"fire-stone":    700,
 		"water-stone":   700,
 		"leaf-stone":    700,
 		"thunder-stone": 700,
 		"moon-stone":    700,
 		"sun-stone":     700,
+		"dusk-stone":    700,
+		"dawn-stone":    700,
+		"shiny-stone":   700,
+		"ice-stone":     700,
+		"exp-share":     3000,
+		"leftovers":     4000,
+		"oran-berry":    100,
+		"sitrus-berry":  400,
+		"lum-berry":     500,
+		"cheri-berry":   200,
+		"rawst-berry":   200,
+		"pecha-berry":   200,
+		"choice-band":   4000,
+		"choice-scarf":  4000,
+		"smoke-ball":    1500,
+		"charcoal":      1000,
+		"mystic-water":  1000,
+		"miracle-seed":  1000,
+		"magnet":        1000,
+		"silk-scarf":    1000,
+		"metal-coat":    2000,
+		"kings-rock":    2000,
+		"dragon-scale":  2000,
+		"up-grade":      2000,
+		"razor-claw":    2000,
+		"razor-fang":    2000,
+		"oval-stone":    2000,
+	}
+
+	// Dusk Balls are only stocked after dark, when they work best
+	dark := game.IsDark(timeOfDay(cfg))
+	if !dark {
+		delete(shopItems, "dusk-ball")
+	}
+
+	// Better stock is only sold to trainers with the right badge
+	for item := range shopItems {
+		if gym, ok := game.UnlockingGym(cfg.Gyms, item); ok && !cfg.Badges.Has(gym) {
+			delete(shopItems, item)
+		}
 	}
 
 	if len(args) == 0 {
-		fmt.Printf("--- Welcome to the PokeMart! --- (Balance: ₽%d)\n", cfg.Inventory.Money)
+		fmt.Printf("--- Welcome to the PokeMart! --- (Balance: ₽%d, %s)\n", cfg.Inventory.Money, timeOfDay(cfg))
 		for item, price := range shopItems {
 			fmt.Printf("- %-12s: ₽%d\n", item, price)
 		}
+		if next, ok := game.NextGym(cfg.Gyms, cfg.Badges); ok && len(next.Unlocks) > 0 {
+			fmt.Printf("\nEarn the %s to buy: %s\n", next.BadgeLabel(), strings.Join(next.Unlocks, ", "))
+		}
 		fmt.Println("\nUsage: shop buy <item_name>")
 		return nil
 	}
@@ -723,9 +1171,22 @@ func commandShop(cfg *Config, args []string) error {
 		itemName := strings.ToLower(args[1])
 		price, exists := shopItems[itemName]
 		if !exists {
+			if gym, ok := game.UnlockingGym(cfg.Gyms, itemName); ok && !cfg.Badges.Has(gym) {
+				return fmt.Errorf("we only sell %s to trainers with the %s", itemName, gym.BadgeLabel())
+			}
+			if itemName == "dusk-ball" && !dark {
+				return fmt.Errorf("dusk-balls are only stocked after dark. Come back in the evening")
+			}
 			return fmt.Errorf("we don't sell %s here", itemName)
 		}
 
+		if itemName == "exp-share" && cfg.Inventory.ExpShare {
+			return fmt.Errorf("you already own an Exp. Share")
+		}
+		if game.IsRod(itemName) && cfg.Inventory.Rods[itemName] {
+			return fmt.Errorf("you already own a %s", itemName)
+		}
+
 		if cfg.Inventory.Money < price {
 			return fmt.Errorf("you don't have enough money! (Needs ₽%d)", price)
 		}
@@ -733,21 +1194,38 @@ func commandShop(cfg *Config, args []string) error {
 		// Deduct money and add item
 		cfg.Inventory.Money -= price
 		switch itemName {
-		case "pokeball":
-			cfg.Inventory.Pokeballs++
-		case "greatball":
-			cfg.Inventory.Greatballs++
-		case "ultraball":
-			cfg.Inventory.Ultraballs++
 		case "potion":
 			cfg.Inventory.Potions++
 		case "superpotion":
 			cfg.Inventory.SuperPotions++
-		case "fire-stone", "water-stone", "leaf-stone", "thunder-stone", "moon-stone", "sun-stone":
+		case "fire-stone", "water-stone", "leaf-stone", "thunder-stone", "moon-stone", "sun-stone",
+			"dusk-stone", "dawn-stone", "shiny-stone", "ice-stone":
 			if cfg.Inventory.EvolutionStones == nil {
 				cfg.Inventory.EvolutionStones = make(map[string]int)
 			}
 			cfg.Inventory.EvolutionStones[itemName]++
+		case "exp-share":
+			cfg.Inventory.ExpShare = true
+			cfg.Inventory.ExpShareOn = true
+		case "repel", "super-repel", "max-repel":
+			if cfg.Inventory.Repels == nil {
+				cfg.Inventory.Repels = make(map[string]int)
+			}
+			cfg.Inventory.Repels[itemName]++
+		case "old-rod", "good-rod", "super-rod":
+			if cfg.Inventory.Rods == nil {
+				cfg.Inventory.Rods = make(map[string]bool)
+			}
+			cfg.Inventory.Rods[itemName] = true
+		default:
+			if game.IsBall(itemName) {
+				cfg.Inventory.AddBall(itemName, 1)
+			} else if game.IsHoldItem(itemName) {
+				if cfg.Inventory.HeldItems == nil {
+					cfg.Inventory.HeldItems = make(map[string]int)
+				}
+				cfg.Inventory.HeldItems[itemName]++
+			}
 		}
 
 		fmt.Printf("Purchased %s! New balance: ₽%d\n", itemName, cfg.Inventory.Money)
@@ -762,30 +1240,90 @@ func commandBattle(config *Config, args []string) error {
 	if len(config.Party) == 0 {
 		return fmt.Errorf("you have no Pokemon in your party! Use 'addteam <name>' first")
 	}
-	if len(args) != 1 {
-		return fmt.Errorf("usage: battle <pokemon_name>")
+	if len(args) > 2 {
+		return fmt.Errorf("usage: battle [pokemon_name] [%s]", strings.Join(game.AINames(), "|"))
 	}
 
-	enemyName := args[0]
-	fmt.Printf("A wild %s appeared!\n", enemyName)
+	// Pick how the opponent fights, defaulting to the type-aware AI
+	opponentAI := game.AI(game.TypeAwareAI{})
+	name := ""
+	for _, arg := range args {
+		if config.Wild != nil && arg == config.Wild.Base.Name {
+			name = arg
+			continue
+		}
+		ai, err := game.NewAI(arg)
+		if err != nil {
+			return err
+		}
+		opponentAI = ai
+	}
 
-	enemyBase, err := config.Pokeapi.GetPokemon(enemyName)
+	wild, err := currentWild(config, name)
 	if err != nil {
 		return err
 	}
 
-	enemyLevel := rand.Intn(5) + 1
+	// Start the battle
+	config.Wild = nil
+	if err := runWildBattle(config, wild.Base, wild.Pokemon, opponentAI); err != nil {
+		return err
+	}
+
+	saveGame(config)
+	return nil
+}
 
-	// Fix: Pass config.Pokeapi and handle the error return
-	wildPokemon, err := game.NewBattlePokemon(enemyBase, enemyLevel, config.Pokeapi)
+// runBattle fills in the player's side of bc, plays the battle in the terminal and saves its replay
+func runBattle(cfg *Config, bc game.BattleConfig) (game.BattleOutcome, error) {
+	bc.Party = cfg.Party
+	bc.Inventory = &cfg.Inventory
+	bc.TimeOfDay = timeOfDay(cfg)
+	bc.Location = cfg.CurrentLocation
+	bc.Client = &cfg.Pokeapi
+	// Each battle gets its own seed so its replay can be reproduced on its own
+	bc.RNG = cfg.RNG.Fork()
+	bc.ObedienceLevel = game.ObedienceLevel(cfg.Gyms, cfg.Badges)
+	bc.ChooseReplacements = true
+
+	battle, events, err := game.NewBattle(bc)
 	if err != nil {
-		return fmt.Errorf("failed to prepare battle: %w", err)
+		return game.OutcomeOngoing, err
 	}
 
-	// Start the battle
-	game.StartBattle(config.Party, wildPokemon, &config.Inventory, config.Pokeapi)
+	ui := tui.BattleUI{In: cfg.Input, Out: os.Stdout, ShakeDelay: 700 * time.Millisecond}
+	ui.PrintEvents(events)
+	outcome := ui.Run(battle)
 
-	saveGame(config)
+	if path, err := saveReplay(battle.Replay()); err != nil {
+		fmt.Printf("Couldn't save the replay: %v\n", err)
+	} else {
+		fmt.Printf("Replay saved to %s\n", path)
+	}
+	return outcome, nil
+}
+
+// runWildBattle plays a wild battle in the terminal and stores the pokemon if it was caught
+func runWildBattle(cfg *Config, wildBase pokeapi.Pokemon, wildMon *game.BattlePokemon, opponentAI game.AI) error {
+	_, alreadyCaught := cfg.CaughtPokemon[wildBase.Name]
+	outcome, err := runBattle(cfg, game.BattleConfig{
+		Wild:          wildMon,
+		OpponentAI:    opponentAI,
+		AlreadyCaught: alreadyCaught,
+	})
+	if err != nil || outcome != game.OutcomeCaught {
+		return err
+	}
+
+	// We add the base data to our CaughtPokemon (the Pokedex tracker)
+	cfg.CaughtPokemon[wildBase.Name] = wildBase
+	if len(cfg.Party) < 6 {
+		cfg.Party = append(cfg.Party, wildMon)
+		fmt.Println("Added to your party!")
+	} else {
+		cfg.PC = append(cfg.PC, wildMon)
+		fmt.Println("Party full. Transferred to PC Box 1!")
+	}
 	return nil
 }
 
@@ -810,14 +1348,49 @@ func getCommands() map[string]cliCommand {
 			description: "Displays a help message",
 			callback:    commandHelp,
 		},
+		"travel": {
+			name:        "travel [region|location|area]",
+			description: "Browse regions and their locations, or travel to an area",
+			callback:    commandTravel,
+		},
+		"where": {
+			name:        "where",
+			description: "Show the area, location and region you're in",
+			callback:    commandWhere,
+		},
 		"explore": {
-			name:        "explore <area_name>",
-			description: "List all Pokemon in a given area",
+			name:        "explore [area_name]",
+			description: "List the wild Pokemon in the current area, or preview another one",
 			callback:    commandExplore,
 		},
+		"walk": {
+			name:        "walk [steps]",
+			description: "Walk through the area's tall grass until a wild Pokemon appears",
+			callback:    commandWalk,
+		},
+		"surf": {
+			name:        "surf [steps]",
+			description: "Swim across the area's water until a wild Pokemon appears",
+			callback:    commandSurf,
+		},
+		"fish": {
+			name:        "fish [rod]",
+			description: "Fish in the area with your best rod, or the one given",
+			callback:    commandFish,
+		},
+		"repel": {
+			name:        "repel [kind]",
+			description: "Use a repel to keep weaker wild Pokemon away while walking or surfing",
+			callback:    commandRepel,
+		},
+		"encounter": {
+			name:        "encounter [method]",
+			description: "Look for a wild Pokemon in the area right away (walk, surf, old-rod, ...)",
+			callback:    commandEncounter,
+		},
 		"catch": {
-			name:        "catch <pokemon_name>",
-			description: "Attempt to catch a pokemon",
+			name:        "catch [pokemon_name]",
+			description: "Attempt to catch the wild Pokemon you ran into",
 			callback:    commandCatch,
 		},
 		"inspect": {
@@ -825,6 +1398,11 @@ func getCommands() map[string]cliCommand {
 			description: "View details about a caught Pokemon",
 			callback:    commandInspect,
 		},
+		"calc": {
+			name:        "calc <attacker> <move> <defender> [level]",
+			description: "Calculate a move's damage; level applies to species not on your team (default 50)",
+			callback:    commandCalc,
+		},
 		"pokedex": {
 			name:        "pokedex",
 			description: "See all the Pokemon you've caught",
@@ -860,6 +1438,16 @@ func getCommands() map[string]cliCommand {
 			description: "Add a pokemon to your team",
 			callback:    commandAddTeam,
 		},
+		"give": {
+			name:        "give <pokemon_name> <item_name>",
+			description: "Give a Pokemon an item from your bag to hold",
+			callback:    commandGive,
+		},
+		"take": {
+			name:        "take <pokemon_name>",
+			description: "Take a Pokemon's held item back to your bag",
+			callback:    commandTake,
+		},
 		"deposit": {
 			name:        "deposit",
 			description: "Move a Pokemon from your party to storage",
@@ -885,16 +1473,51 @@ func getCommands() map[string]cliCommand {
 			description: "Evolves your pokemon using stones",
 			callback:    commandEvolve,
 		},
+		"trade": {
+			name:        "trade <pokemon_name>",
+			description: "Trade a Pokemon with a friend and get it back (triggers trade evolutions)",
+			callback:    commandTrade,
+		},
 		"shop": {
 			name:        "shop",
 			description: "Opens the shop",
 			callback:    commandShop,
 		},
+		"expshare": {
+			name:        "expshare [on|off]",
+			description: "Toggle the Exp. Share for benched party members",
+			callback:    commandExpShare,
+		},
 		"battle": {
-			name:        "battle",
-			description: "Start a pokemon battle",
+			name:        "battle [pokemon_name] [ai]",
+			description: "Fight the wild Pokemon you ran into (ai: random, greedy, smart, trainer)",
 			callback:    commandBattle,
 		},
+		"trainers": {
+			name:        "trainers",
+			description: "List the trainers you can challenge",
+			callback:    commandTrainers,
+		},
+		"challenge": {
+			name:        "challenge <trainer>",
+			description: "Battle a trainer and their team for prize money",
+			callback:    commandChallenge,
+		},
+		"gym": {
+			name:        "gym [number]",
+			description: "List the gyms, or challenge a gym leader for their badge",
+			callback:    commandGym,
+		},
+		"badges": {
+			name:        "badges",
+			description: "Open your badge case",
+			callback:    commandBadges,
+		},
+		"replay": {
+			name:        "replay [file] [speed]",
+			description: "List saved battle replays, or play one back (speed 2 = twice as fast)",
+			callback:    commandReplay,
+		},
 		"exit": {
 			name:        "exit",
 			description: "Exit the Pokedex",
@@ -902,3 +1525,190 @@ func getCommands() map[string]cliCommand {
 		},
 	}
 }
+
+const replayDir = "replays"
+
+// replayDelay is the pause between events when replaying at speed 1
+const replayDelay = 800 * time.Millisecond
+
+func saveReplay(r game.Replay) (string, error) {
+	if err := os.MkdirAll(replayDir, 0755); err != nil {
+		return "", err
+	}
+	fileData, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.json", r.Recorded.Format("20060102-150405"), slugify(r.Opponent))
+	path := filepath.Join(replayDir, name)
+	return path, os.WriteFile(path, fileData, 0644)
+}
+
+// slugify makes a name safe for a file the replay command can be given,
+// e.g. "Twins Amy & May" becomes "twins-amy-may"
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func loadReplay(name string) (game.Replay, error) {
+	var r game.Replay
+
+	// Accept bare names from the replay list as well as paths
+	path := name
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Join(replayDir, name)
+	}
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(fileData, &r); err != nil {
+		return r, fmt.Errorf("%s is not a replay file: %w", name, err)
+	}
+	return r, nil
+}
+
+func commandReplay(cfg *Config, args []string) error {
+	if len(args) == 0 {
+		entries, err := os.ReadDir(replayDir)
+		if err != nil || len(entries) == 0 {
+			fmt.Println("No replays saved yet. Every battle you fight is recorded!")
+			return nil
+		}
+		fmt.Println("Saved replays:")
+		for _, entry := range entries {
+			fmt.Printf(" - %s\n", entry.Name())
+		}
+		return nil
+	}
+	if len(args) > 2 {
+		return fmt.Errorf("usage: replay [file] [speed]")
+	}
+
+	speed := 1.0
+	if len(args) == 2 {
+		s, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || s <= 0 {
+			return fmt.Errorf("speed must be a positive number")
+		}
+		speed = s
+	}
+
+	r, err := loadReplay(args[0])
+	if err != nil {
+		return err
+	}
+
+	ui := tui.BattleUI{Out: os.Stdout}
+	ui.PlayReplay(r, time.Duration(float64(replayDelay)/speed))
+	return nil
+}
+
+// calcDefaultLevel is used for species that aren't in the party or PC
+const calcDefaultLevel = 50
+
+func commandCalc(cfg *Config, args []string) error {
+	if len(args) < 3 || len(args) > 4 {
+		return fmt.Errorf("usage: calc <attacker> <move> <defender> [level]")
+	}
+
+	level := calcDefaultLevel
+	if len(args) == 4 {
+		l, err := strconv.Atoi(args[3])
+		if err != nil || l < 1 || l > game.MaxLevel {
+			return fmt.Errorf("level must be between 1 and %d", game.MaxLevel)
+		}
+		level = l
+	}
+
+	attacker, err := calcPokemon(cfg, args[0], level)
+	if err != nil {
+		return err
+	}
+	defender, err := calcPokemon(cfg, args[2], level)
+	if err != nil {
+		return err
+	}
+
+	// Prefer the attacker's own copy of the move so PP and held items match
+	var move *game.Move
+	for i := range attacker.Moves {
+		if attacker.Moves[i].Name == args[1] {
+			move = &attacker.Moves[i]
+		}
+	}
+	if move == nil {
+		apiMove, err := cfg.Pokeapi.GetMove(args[1])
+		if err != nil {
+			return fmt.Errorf("couldn't find move %s: %w", args[1], err)
+		}
+		m := game.NewMove(apiMove)
+		move = &m
+	}
+
+	printDamageCalc(os.Stdout, attacker, defender, move)
+	return nil
+}
+
+// printDamageCalc reports what one move does to a defender, using the battle's damage code
+func printDamageCalc(out io.Writer, attacker, defender *game.BattlePokemon, move *game.Move) {
+	fmt.Fprintf(out, "Lvl %d %s's %s vs Lvl %d %s (%d/%d HP)\n",
+		attacker.Level, attacker.Nickname, move.Name, defender.Level, defender.Nickname, defender.Stats.HP, defender.Stats.MaxHP)
+
+	dmg := game.CalculateDamageRange(attacker, defender, move)
+	if dmg.Effectiveness == 0 {
+		fmt.Fprintf(out, "It doesn't affect %s...\n", defender.Nickname)
+		return
+	}
+	// Friendship moves like Return have no listed power, so ask the damage code rather than the move
+	if dmg.Max == 0 {
+		fmt.Fprintf(out, "%s doesn't deal direct damage.\n", move.Name)
+		return
+	}
+
+	maxHP := float64(defender.Stats.MaxHP)
+	fmt.Fprintf(out, "Damage: %d-%d (%.1f%%-%.1f%%)\n", dmg.Min, dmg.Max, float64(dmg.Min)/maxHP*100, float64(dmg.Max)/maxHP*100)
+	fmt.Fprintf(out, "Critical hit: %d-%d (%.1f%%-%.1f%%)\n", dmg.CritMin, dmg.CritMax, float64(dmg.CritMin)/maxHP*100, float64(dmg.CritMax)/maxHP*100)
+	switch {
+	case dmg.Effectiveness > 1:
+		fmt.Fprintf(out, "It's super effective! (x%g)\n", dmg.Effectiveness)
+	case dmg.Effectiveness < 1:
+		fmt.Fprintf(out, "It's not very effective... (x%g)\n", dmg.Effectiveness)
+	}
+
+	best, worst := dmg.HitsToKO(defender.Stats.HP)
+	if defender.Stats.HP <= 0 {
+		fmt.Fprintf(out, "%s has already fainted.\n", defender.Nickname)
+	} else if best == worst {
+		fmt.Fprintf(out, "Guaranteed %dHKO\n", best)
+	} else {
+		fmt.Fprintf(out, "%dHKO to %dHKO\n", best, worst)
+	}
+}
+
+// calcPokemon finds a party or PC member, or builds a full-HP species at the given level
+func calcPokemon(cfg *Config, name string, level int) (*game.BattlePokemon, error) {
+	if p := findPartyOrPC(cfg, name); p != nil {
+		return p, nil
+	}
+
+	base, err := cfg.Pokeapi.GetPokemon(name)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find %s on your team or in the Pokedex API: %w", name, err)
+	}
+	p := &game.BattlePokemon{Base: base, Nickname: base.Name, Level: level}
+	p.RecalculateStats()
+	p.Stats.HP = p.Stats.MaxHP
+	return p, nil
+}
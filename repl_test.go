@@ -1,6 +1,12 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+)
 
 func TestCleanInput(t *testing.T) {
 	cases := []struct {
@@ -37,3 +43,52 @@ func TestCleanInput(t *testing.T) {
 		}
 	}
 }
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "pidgey", expected: "pidgey"},
+		{input: "Youngster Joey", expected: "youngster-joey"},
+		{input: "Twins Amy & May", expected: "twins-amy-may"},
+		{input: "Mr. Mime ", expected: "mr-mime"},
+	}
+	for _, c := range cases {
+		if actual := slugify(c.input); actual != c.expected {
+			t.Errorf("slugify(%q): got %q, expected %q", c.input, actual, c.expected)
+		}
+	}
+}
+
+func TestPrintDamageCalc(t *testing.T) {
+	newMon := func(name string, friendship int) *game.BattlePokemon {
+		p := &game.BattlePokemon{
+			Nickname:   name,
+			Level:      50,
+			Friendship: friendship,
+			Stats:      game.Stats{HP: 150, MaxHP: 150, Attack: 100, Defense: 100, Speed: 100},
+		}
+		p.Base.Name = name
+		return p
+	}
+	// Return and Growl both come from the API without power
+	returnMove := &game.Move{Name: "return", Type: "normal", Accuracy: 100}
+	growl := &game.Move{Name: "growl", Type: "normal", Accuracy: 100}
+
+	cases := []struct {
+		attacker *game.BattlePokemon
+		move     *game.Move
+		expected string
+	}{
+		{attacker: newMon("eevee", game.MaxFriendship), move: returnMove, expected: "Damage: "},
+		{attacker: newMon("eevee", game.MaxFriendship), move: growl, expected: "growl doesn't deal direct damage."},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		printDamageCalc(&out, c.attacker, newMon("rattata", game.DefaultFriendship), c.move)
+		if !strings.Contains(out.String(), c.expected) {
+			t.Errorf("calc with %s: expected %q in:\n%s", c.move.Name, c.expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+	pokeapi "github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+// runSimulate is the headless `simulate` subcommand:
+//
+//	bootdev-pokedex [--seed N] simulate [-n 1000] [-ai smart] [-foe-ai smart] [-double] charmander:12,pidgey:10 squirtle:12,rattata:9
+func runSimulate(client pokeapi.Client, rng *game.RNG, args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	battles := fs.Int("n", 1000, "number of battles to simulate")
+	teamAIName := fs.String("ai", "smart", "AI playing the team: "+strings.Join(game.AINames(), ", "))
+	foeAIName := fs.String("foe-ai", "smart", "AI playing the opponent")
+	maxTurns := fs.Int("max-turns", game.DefaultMaxTurns, "turns before a battle counts as a draw")
+	double := fs.Bool("double", false, "battle two pokemon a side")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: simulate [flags] <pokemon:level,...> <pokemon:level,...>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || *battles < 1 {
+		fs.Usage()
+		return fmt.Errorf("simulate needs two teams and at least one battle")
+	}
+
+	teamAI, err := game.NewAI(*teamAIName)
+	if err != nil {
+		return err
+	}
+	foeAI, err := game.NewAI(*foeAIName)
+	if err != nil {
+		return err
+	}
+
+	team, err := parseSimulationTeam(client, rng, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	foes, err := parseSimulationTeam(client, rng, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	result, err := game.Simulate(game.SimulationConfig{
+		Team:       team,
+		Opponents:  foes,
+		TeamAI:     teamAI,
+		OpponentAI: foeAI,
+		Battles:    *battles,
+		MaxTurns:   *maxTurns,
+		Double:     *double,
+		RNG:        rng,
+	})
+	if err != nil {
+		return err
+	}
+
+	printSimulation(team, foes, result)
+	return nil
+}
+
+// parseSimulationTeam builds pokemon from "name:level,name:level"
+func parseSimulationTeam(client pokeapi.Client, rng *game.RNG, spec string) ([]*game.BattlePokemon, error) {
+	var team []*game.BattlePokemon
+	for _, entry := range strings.Split(strings.ToLower(spec), ",") {
+		name, levelStr, found := strings.Cut(entry, ":")
+		level := 5
+		if found {
+			l, err := strconv.Atoi(levelStr)
+			if err != nil || l < 1 || l > game.MaxLevel {
+				return nil, fmt.Errorf("invalid level for %s: %s", name, levelStr)
+			}
+			level = l
+		}
+
+		base, err := client.GetPokemon(name)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't fetch %s: %w", name, err)
+		}
+		p, err := game.NewBattlePokemon(base, level, client, rng)
+		if err != nil {
+			return nil, err
+		}
+		team = append(team, p)
+	}
+	return team, nil
+}
+
+func printSimulation(team, foes []*game.BattlePokemon, result game.SimulationResult) {
+	fmt.Printf("Simulated %d battles: %s vs %s\n", result.Battles, describeSimulatedTeam(team), describeSimulatedTeam(foes))
+	fmt.Printf("Win rate: %.1f%% (%d won, %d lost, %d drawn)\n", result.WinRate()*100, result.Wins, result.Losses, result.Draws)
+	fmt.Printf("Average turns: %.1f\n", result.AverageTurns())
+
+	fmt.Println("\nDamage per hit:")
+	fmt.Printf("%-9s %-16s %7s %6s %6s %5s %5s %5s %5s %5s %7s\n", "Side", "Move", "Uses", "Miss%", "Crit%", "Min", "P10", "Med", "P90", "Max", "Mean")
+	for _, d := range result.Damage {
+		hits := len(d.Damages)
+		fmt.Printf("%-9s %-16s %7d %6.1f %6.1f %5d %5d %5d %5d %5d %7.1f\n",
+			d.Side, d.Move, d.Uses,
+			percent(d.Misses, d.Uses), percent(d.Crits, hits),
+			d.Percentile(0), d.Percentile(10), d.Percentile(50), d.Percentile(90), d.Percentile(100), d.Mean())
+	}
+}
+
+func describeSimulatedTeam(team []*game.BattlePokemon) string {
+	names := make([]string, len(team))
+	for i, p := range team {
+		moves := make([]string, len(p.Moves))
+		for j, m := range p.Moves {
+			moves[j] = m.Name
+		}
+		names[i] = fmt.Sprintf("%s (Lvl %d, %s)", p.Nickname, p.Level, strings.Join(moves, "/"))
+	}
+	return strings.Join(names, ", ")
+}
+
+func percent(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
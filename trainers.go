@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Bloodisck/bootdev-pokedex/internal/game"
+)
+
+func commandTrainers(cfg *Config, args []string) error {
+	fmt.Println("Trainers looking for a battle:")
+	for _, t := range cfg.Trainers {
+		status := fmt.Sprintf("prize ₽%d", t.PrizeMoney())
+		if cfg.DefeatedTrainers[t.ID] {
+			status = "defeated"
+		}
+		if t.Double {
+			status += ", double battle"
+		}
+		fmt.Printf(" - %s: %s, %d Pokemon (%s)\n", t.ID, t.Title(), len(t.Team), status)
+	}
+	return nil
+}
+
+func commandChallenge(cfg *Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: challenge <trainer>")
+	}
+	trainer, ok := game.FindTrainer(cfg.Trainers, args[0])
+	if !ok {
+		return fmt.Errorf("no trainer called %s. Use 'trainers' to see who's around", args[0])
+	}
+	if cfg.DefeatedTrainers[trainer.ID] {
+		return fmt.Errorf("%s has already been defeated", trainer.Title())
+	}
+
+	fmt.Printf("Getting %s's team ready...\n", trainer.Title())
+	team, err := trainer.BuildTeam(cfg.Pokeapi, cfg.RNG)
+	if err != nil {
+		return err
+	}
+
+	outcome, err := runBattle(cfg, game.BattleConfig{
+		Trainer:     &trainer,
+		TrainerTeam: team,
+		Double:      trainer.Double,
+	})
+	if err != nil {
+		return err
+	}
+	if outcome == game.OutcomeWon {
+		cfg.DefeatedTrainers[trainer.ID] = true
+	}
+	return saveGame(cfg)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+
+	pokeapi "github.com/Bloodisck/bootdev-pokedex/internal/pokeapi"
+)
+
+// commandTravel browses the world from regions down to areas, and moves the player to an area:
+//
+//	travel                  lists the regions
+//	travel kanto            lists kanto's locations
+//	travel kanto-route-1    lists the route's areas, or goes straight there if it has just one
+//	travel kanto-route-1-area
+func commandTravel(cfg *Config, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: travel [region, location or area]")
+	}
+	if len(args) == 0 {
+		regions, err := cfg.Pokeapi.GetRegions()
+		if err != nil {
+			return err
+		}
+		fmt.Println("Regions:")
+		for _, r := range regions.Results {
+			fmt.Printf(" - %s\n", r.Name)
+		}
+		fmt.Println("Use 'travel <region>' to see its locations.")
+		return nil
+	}
+
+	name := args[0]
+	if area, err := cfg.Pokeapi.GetLocationArea(name); err == nil {
+		return moveTo(cfg, area)
+	}
+
+	if location, err := cfg.Pokeapi.GetLocation(name); err == nil {
+		if len(location.Areas) == 1 {
+			area, err := cfg.Pokeapi.GetLocationArea(location.Areas[0].Name)
+			if err != nil {
+				return err
+			}
+			return moveTo(cfg, area)
+		}
+		if len(location.Areas) == 0 {
+			return fmt.Errorf("there's nowhere to go in %s", location.Name)
+		}
+		fmt.Printf("Areas in %s:\n", location.Name)
+		printNames(location.Areas)
+		fmt.Println("Use 'travel <area>' to go to one.")
+		return nil
+	}
+
+	region, err := cfg.Pokeapi.GetRegion(name)
+	if err != nil {
+		return fmt.Errorf("no region, location or area called %s", name)
+	}
+	fmt.Printf("Locations in %s:\n", region.Name)
+	printNames(region.Locations)
+	fmt.Println("Use 'travel <location>' to see its areas.")
+	return nil
+}
+
+// moveTo takes the player to an area and saves
+func moveTo(cfg *Config, area pokeapi.LocationAreaDetail) error {
+	setArea(cfg, area)
+	fmt.Printf("You traveled to %s.\n", area.Name)
+	fmt.Println("Use 'explore' to see what lives here, or 'walk' to look for wild Pokemon.")
+	return saveGame(cfg)
+}
+
+// setArea puts the player in an area, leaving any wild pokemon behind
+func setArea(cfg *Config, area pokeapi.LocationAreaDetail) {
+	if area.Name == cfg.CurrentArea {
+		return
+	}
+	cfg.CurrentArea = area.Name
+	cfg.CurrentLocation = area.Location.Name
+	cfg.CurrentRegion = ""
+	if location, err := cfg.Pokeapi.GetLocation(area.Location.Name); err == nil {
+		cfg.CurrentRegion = location.Region.Name
+	}
+	cfg.Wild = nil
+}
+
+// commandWhere shows where the player is, and the other areas of the same location
+func commandWhere(cfg *Config, args []string) error {
+	if cfg.CurrentArea == "" {
+		fmt.Println("You haven't gone anywhere yet. Use 'travel' to pick a region.")
+		return nil
+	}
+
+	fmt.Printf("Area:     %s\n", cfg.CurrentArea)
+	fmt.Printf("Location: %s\n", valueOr(cfg.CurrentLocation, "unknown"))
+	fmt.Printf("Region:   %s\n", valueOr(cfg.CurrentRegion, "unknown"))
+	fmt.Printf("Time:     %s\n", timeOfDay(cfg))
+
+	location, err := cfg.Pokeapi.GetLocation(cfg.CurrentLocation)
+	if err != nil || len(location.Areas) < 2 {
+		return nil
+	}
+	fmt.Println("Nearby areas:")
+	for _, a := range location.Areas {
+		if a.Name != cfg.CurrentArea {
+			fmt.Printf(" - %s\n", a.Name)
+		}
+	}
+	return nil
+}
+
+func printNames(resources []pokeapi.NamedAPIResource) {
+	for _, r := range resources {
+		fmt.Printf(" - %s\n", r.Name)
+	}
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}